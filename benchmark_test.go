@@ -2,6 +2,8 @@ package alpacadecimal_test
 
 import (
 	"database/sql/driver"
+	"math/rand"
+	"strconv"
 	"testing"
 
 	"github.com/alpacahq/alpacadecimal"
@@ -36,6 +38,18 @@ func BenchmarkValue(b *testing.B) {
 		_ = result
 	})
 
+	b.Run("alpacadecimal.Decimal Whole Number Case", func(b *testing.B) {
+		d := alpacadecimal.NewFromInt(100000) // e.g. share counts, out of cache range.
+
+		var result driver.Value
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = d.Value()
+		}
+		_ = result
+	})
+
 	b.Run("alpacadecimal.Decimal Fallback Case", func(b *testing.B) {
 		d := alpacadecimal.NewFromInt(123456789) // this larger than max supported optimized value.
 
@@ -168,6 +182,17 @@ func BenchmarkScan(b *testing.B) {
 		_ = err
 	})
 
+	b.Run("alpacadecimal.Decimal.Parse", func(b *testing.B) {
+		buf := []byte(source.(string))
+
+		var err error
+		for n := 0; n < b.N; n++ {
+			var d alpacadecimal.Decimal
+			err = d.Parse(buf)
+		}
+		_ = err
+	})
+
 	b.Run("decimal.Decimal", func(b *testing.B) {
 		var err error
 		for n := 0; n < b.N; n++ {
@@ -279,6 +304,82 @@ func BenchmarkDiv(b *testing.B) {
 	})
 }
 
+func BenchmarkDivLarge(b *testing.B) {
+	// exact quotient, beyond float64 precision
+	x := "6847245.80224622965"
+	y := "2"
+
+	b.Run("alpacadecimal.Decimal", func(b *testing.B) {
+		d1 := alpacadecimal.RequireFromString(x)
+		d2 := alpacadecimal.RequireFromString(y)
+
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Div(d2)
+		}
+		_ = result
+	})
+
+	b.Run("decimal.Decimal", func(b *testing.B) {
+		d1 := decimal.RequireFromString(x)
+		d2 := decimal.RequireFromString(y)
+
+		var result decimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Div(d2)
+		}
+		_ = result
+	})
+}
+
+func BenchmarkDivToScale(b *testing.B) {
+	x := "20.00"
+	y := "3.00"
+
+	b.Run("alpacadecimal.Decimal", func(b *testing.B) {
+		d1 := alpacadecimal.RequireFromString(x)
+		d2 := alpacadecimal.RequireFromString(y)
+
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = d1.DivToScale(d2, -2, alpacadecimal.RoundHalfEven)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal.Div.RoundBank", func(b *testing.B) {
+		d1 := alpacadecimal.RequireFromString(x)
+		d2 := alpacadecimal.RequireFromString(y)
+
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Div(d2).RoundBank(2)
+		}
+		_ = result
+	})
+
+	b.Run("decimal.Decimal", func(b *testing.B) {
+		d1 := decimal.RequireFromString(x)
+		d2 := decimal.RequireFromString(y)
+
+		var result decimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Div(d2).RoundBank(2)
+		}
+		_ = result
+	})
+}
+
 func BenchmarkString(b *testing.B) {
 	x := 1.23
 
@@ -360,3 +461,386 @@ func BenchmarkRound(b *testing.B) {
 		_ = result
 	})
 }
+
+func BenchmarkParseAll(b *testing.B) {
+	source := []string{"0", "0.00", "1.23", "-12345.123456789", "1000000", "0.000001"}
+
+	b.Run("alpacadecimal.ParseAll", func(b *testing.B) {
+		var result []alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = alpacadecimal.ParseAll(source)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.NewFromString", func(b *testing.B) {
+		var result []alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = make([]alpacadecimal.Decimal, len(source))
+			for i, s := range source {
+				result[i], _ = alpacadecimal.NewFromString(s)
+			}
+		}
+		_ = result
+	})
+}
+
+func BenchmarkMulInt(b *testing.B) {
+	x := 1.23
+	y := int64(100)
+
+	b.Run("alpacadecimal.Decimal.MulInt", func(b *testing.B) {
+		d1 := alpacadecimal.NewFromFloat(x)
+
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.MulInt(y)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal.Mul", func(b *testing.B) {
+		d1 := alpacadecimal.NewFromFloat(x)
+
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Mul(alpacadecimal.NewFromInt(y))
+		}
+		_ = result
+	})
+}
+
+func BenchmarkNewFromStringCached(b *testing.B) {
+	// zipf distributed inputs over 1000 distinct values,
+	// where a few values like "0.00", "1.00" dominate.
+	r := rand.New(rand.NewSource(1))
+	z := rand.NewZipf(r, 1.1, 1, 999)
+	source := make([]string, 10000)
+	for i := range source {
+		source[i] = strconv.FormatFloat(float64(z.Uint64())*1.01, 'f', 2, 64)
+	}
+
+	b.Run("alpacadecimal.NewFromStringCached", func(b *testing.B) {
+		defer alpacadecimal.ClearParseCache()
+
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = alpacadecimal.NewFromStringCached(source[n%len(source)])
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.NewFromString", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = alpacadecimal.NewFromString(source[n%len(source)])
+		}
+		_ = result
+	})
+}
+
+func BenchmarkStringWholeNumber(b *testing.B) {
+	d1 := alpacadecimal.NewFromInt(123456)
+
+	b.Run("alpacadecimal.Decimal", func(b *testing.B) {
+		var result string
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.String()
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal large", func(b *testing.B) {
+		d := alpacadecimal.NewFromInt(5_000_000)
+
+		var result string
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d.String()
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal negative", func(b *testing.B) {
+		d := alpacadecimal.NewFromInt(-5_000_000)
+
+		var result string
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d.String()
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal with EnableIntCache", func(b *testing.B) {
+		alpacadecimal.EnableIntCache(1_000_000)
+		defer alpacadecimal.EnableIntCache(0)
+
+		var result string
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.String()
+		}
+		_ = result
+	})
+}
+
+func BenchmarkMulRound(b *testing.B) {
+	d1 := alpacadecimal.RequireFromString("1234.123456")
+	d2 := alpacadecimal.RequireFromString("0.0000345678")
+
+	b.Run("alpacadecimal.Decimal.MulRound", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.MulRound(d2, 2)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal.Mul.Round", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Mul(d2).Round(2)
+		}
+		_ = result
+	})
+}
+
+func BenchmarkEqualMixed(b *testing.B) {
+	d1 := alpacadecimal.RequireFromString("1234.5")
+	d2 := alpacadecimal.NewFromDecimal(decimal.RequireFromString("1234.50"))
+	// fallback-tagged value holding an in-range number
+	d3 := alpacadecimal.RequireFromString("123458023.5").Sub(alpacadecimal.RequireFromString("123456789"))
+	d4 := alpacadecimal.RequireFromString("1234.56")
+
+	b.Run("alpacadecimal.Decimal.optimized", func(b *testing.B) {
+		var result bool
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Equal(d2)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal.fallback", func(b *testing.B) {
+		var result bool
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d1.Equal(d3)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal.fallback.unequal", func(b *testing.B) {
+		var result bool
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = d4.Equal(d3)
+		}
+		_ = result
+	})
+}
+
+func BenchmarkKahanSum(b *testing.B) {
+	// ledger with intermediate sums out of optimized range
+	ds := make([]alpacadecimal.Decimal, 0, 1000)
+	for i := 0; i < 500; i++ {
+		ds = append(ds, alpacadecimal.RequireFromString("1000000.01"))
+	}
+	for i := 0; i < 500; i++ {
+		ds = append(ds, alpacadecimal.RequireFromString("-1000000"))
+	}
+
+	b.Run("alpacadecimal.KahanSum", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = alpacadecimal.KahanSum(ds)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Sum", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = alpacadecimal.Sum(ds[0], ds[1:]...)
+		}
+		_ = result
+	})
+
+	// fallback values
+	fs := make([]alpacadecimal.Decimal, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		fs = append(fs, alpacadecimal.RequireFromString("0.0000000000001"))
+	}
+
+	b.Run("alpacadecimal.KahanSum fallback", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = alpacadecimal.KahanSum(fs)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Sum fallback", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = alpacadecimal.Sum(fs[0], fs[1:]...)
+		}
+		_ = result
+	})
+}
+
+func BenchmarkToFloat64Slice(b *testing.B) {
+	ds := make([]alpacadecimal.Decimal, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		ds = append(ds, alpacadecimal.New(int64(i)*1427, -2))
+	}
+
+	b.Run("alpacadecimal.ToFloat64Slice", func(b *testing.B) {
+		var result []float64
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = alpacadecimal.ToFloat64Slice(ds)
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal.InexactFloat64", func(b *testing.B) {
+		var result []float64
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = make([]float64, len(ds))
+			for i, d := range ds {
+				result[i] = d.InexactFloat64()
+			}
+		}
+		_ = result
+	})
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	// fallback-heavy chain, e.g. notional in a currency with a large exchange rate
+	price := alpacadecimal.RequireFromString("123456789.123456789")
+	qty := alpacadecimal.RequireFromString("1.5")
+	fee := alpacadecimal.RequireFromString("0.000000000000125")
+	rate := alpacadecimal.RequireFromString("1350.25")
+
+	b.Run("alpacadecimal.Builder", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			builder := alpacadecimal.NewBuilder(price)
+			result = builder.Mul(qty).Sub(fee).Mul(rate).Add(fee).Decimal()
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal", func(b *testing.B) {
+		var result alpacadecimal.Decimal
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result = price.Mul(qty).Sub(fee).Mul(rate).Add(fee)
+		}
+		_ = result
+	})
+}
+
+func BenchmarkAppendFixed(b *testing.B) {
+	d := alpacadecimal.RequireFromString("1234.5678")
+	buf := make([]byte, 0, 64)
+
+	b.Run("alpacadecimal.Decimal.AppendFixed", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			buf = d.AppendFixed(buf[:0], 2)
+		}
+	})
+
+	b.Run("alpacadecimal.Decimal.StringFixed", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			buf = append(buf[:0], d.StringFixed(2)...)
+		}
+	})
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	d := alpacadecimal.RequireFromString("1234.5678")
+
+	b.Run("alpacadecimal.Decimal", func(b *testing.B) {
+		var result []byte
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = d.MarshalJSON()
+		}
+		_ = result
+	})
+
+	b.Run("alpacadecimal.Decimal cached", func(b *testing.B) {
+		d := alpacadecimal.RequireFromString("12.34")
+
+		var result []byte
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = d.MarshalJSON()
+		}
+		_ = result
+	})
+
+	b.Run("decimal.Decimal", func(b *testing.B) {
+		d := decimal.RequireFromString("1234.5678")
+
+		var result []byte
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			result, _ = d.MarshalJSON()
+		}
+		_ = result
+	})
+}
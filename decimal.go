@@ -1,11 +1,23 @@
 package alpacadecimal
 
 import (
+	"bufio"
+	"database/sql"
 	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"math/big"
+	"math/bits"
+	"os"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/shopspring/decimal"
 )
@@ -35,28 +47,46 @@ var pow10Table []int64 = []int64{
 // cache value from -1000.00 to 1000.00
 // with
 //
-//	`valueCache[0] = "-1000"`
-//	`valueCache[100000] = "0"`
-//	`valueCache[200000] = "1000"`
+//	`stringCache[0] = "-1000"`
+//	`stringCache[100000] = "0"`
+//	`stringCache[200000] = "1000"`
 //
-// this consumes about 9 MB in memory with pprof check.
+// this consumes about 5 MB in memory.
 const (
 	cacheSize   = 200001
 	cacheOffset = 100000
 )
 
-var (
-	valueCache  [cacheSize]driver.Value
-	stringCache [cacheSize]string
-)
+var stringCache [cacheSize]string
+
+// optional second cache tier for whole numbers from -max to max, see EnableIntCache.
+// this consumes about 24 bytes per number, e.g. about 48 MB with max = 1_000_000.
+var intCache atomic.Value // *intStringCache
+
+type intStringCache struct {
+	max     int64
+	strings []string // strings[i] = strconv.FormatInt(i - max, 10)
+}
+
+// the cache is built eagerly at init by default. small CLI tools or serverless
+// functions can set env `ALPACADECIMAL_LAZY_CACHE=1` to skip this startup cost,
+// then the cache is built on first cache hit of String / Value instead,
+// see BenchmarkInitCache for the cost.
+var cacheOnce sync.Once
 
 func init() {
 	// init cache
-	for i := 0; i < cacheSize; i++ {
-		str := strconv.FormatFloat(float64(i-cacheOffset)/100, 'f', -1, 64)
+	if os.Getenv("ALPACADECIMAL_LAZY_CACHE") == "" {
+		cacheOnce.Do(initCache)
+	}
 
-		valueCache[i] = str
-		stringCache[i] = str
+	// init parse cache, see NewFromStringCached
+	parseCache.Store(&stringParseCache{})
+}
+
+func initCache() {
+	for i := 0; i < cacheSize; i++ {
+		stringCache[i] = strconv.FormatFloat(float64(i-cacheOffset)/100, 'f', -1, 64)
 	}
 }
 
@@ -73,6 +103,47 @@ var (
 	ExpMaxIterations         = decimal.ExpMaxIterations
 	MarshalJSONWithoutQuotes = decimal.MarshalJSONWithoutQuotes
 	Zero                     = Decimal{fixed: 0}
+
+	// ErrOutOfRange is returned by the "Exact" variants (e.g. NewFromIntExact, AddExact)
+	// when the result can not be represented by the optimized fixed format,
+	// where the non-error variants would fallback to decimal.Decimal instead.
+	ErrOutOfRange = errors.New("decimal out of optimized range")
+
+	// StrictFloatScan makes Scan return an error for float32 / float64 sources
+	// which NewFromFloatExact rejects, i.e. can not be converted with 12 precision
+	// within the optimized range, instead of falling back to an approximation.
+	//
+	// Float DB columns are approximate by nature, e.g. a float32 column holding 0.1
+	// is 0.100000001490116119384765625, which is rejected in strict mode.
+	// prefer numeric / decimal columns where possible.
+	StrictFloatScan = false
+
+	// MarshalBinaryCompact makes MarshalBinary and GobEncode write the versioned compact format,
+	// i.e. a version byte followed by the 8 bytes of the fixed value for optimized values,
+	// instead of the decimal.Decimal binary format.
+	//
+	// UnmarshalBinary and GobDecode accept both formats regardless of this setting,
+	// so for shared caches, first deploy the decoder everywhere, then enable this.
+	MarshalBinaryCompact = false
+
+	// OnFallback, if set, is called with the name of the operation whenever it falls back
+	// to decimal.Decimal, e.g. to emit metrics of how often a workload leaves the optimized path.
+	// The reported operations are:
+	//
+	//	"New", "NewFromInt", "NewFromFloat", "NewFromFloat32", "NewFromBigInt", "NewFromDecimal", "NewFromString", "Pow10"
+	//	"Parse", "ParseAll", "ParsePrefix", "Decode", "Scan", "UnmarshalJSON", "UnmarshalText", "UnmarshalBinary"
+	//	"Add", "Sub", "Mul", "Div", "MulInt", "MulPow10", "MulRound", "FMA", "AbsDiff", "KahanSum"
+	//	"DivMod", "DivRoundBank", "DivToScale", "SplitN"
+	//	"Neg", "Abs", "RoundWithMode", "Ceil", "Floor", "Truncate", "RoundCash", "Quantize"
+	//
+	// Only the innermost operation is reported, e.g. Scan of a float64 reports "NewFromFloat",
+	// Round reports "RoundWithMode", DivPow10 reports "MulPow10", DivInt and Reciprocal report "Div",
+	// GobDecode reports "UnmarshalBinary" and Builder reports its Add, Sub, Mul or Div.
+	// Operations which always fall back, e.g. Mod, Pow or Sin, are not reported,
+	// neither are Copy and Canonical, which keep the representation of d.
+	//
+	// It must be set before use, e.g. in an init func, and must be safe for concurrent use.
+	OnFallback func(op string)
 )
 
 func RescalePair(d1 Decimal, d2 Decimal) (Decimal, Decimal) {
@@ -83,6 +154,28 @@ func RescalePair(d1 Decimal, d2 Decimal) (Decimal, Decimal) {
 	return newFromDecimal(dd1), newFromDecimal(dd2)
 }
 
+// EnableIntCache builds a second cache tier for String / Value of whole numbers
+// from -max to max, e.g. share quantities, which miss the default cache
+// of -1000.00 to 1000.00. max is capped to the optimized range,
+// and max <= 0 disables the tier again.
+//
+// It is meant to be called once at startup, see intCache for memory footprint.
+func EnableIntCache(max int64) {
+	if max <= 0 {
+		intCache.Store((*intStringCache)(nil))
+		return
+	}
+	if max > maxInt {
+		max = maxInt
+	}
+
+	c := &intStringCache{max: max, strings: make([]string, 2*max+1)}
+	for i := range c.strings {
+		c.strings[i] = strconv.FormatInt(int64(i)-max, 10)
+	}
+	intCache.Store(c)
+}
+
 type Decimal struct {
 	// fallback to original decimal.Decimal if necessary
 	fallback *decimal.Decimal
@@ -93,12 +186,203 @@ type Decimal struct {
 	fixed int64
 }
 
+// RoundingMode specifies how RoundWithMode rounds a decimal.
+type RoundingMode int
+
+const (
+	// RoundHalfAwayFromZero rounds to nearest, ties away from zero. Same as Round.
+	RoundHalfAwayFromZero RoundingMode = iota
+	// RoundHalfEven rounds to nearest, ties to even. Same as RoundBank.
+	RoundHalfEven
+	// RoundHalfUp rounds to nearest, ties towards +infinity.
+	RoundHalfUp
+	// RoundCeil rounds towards +infinity. Same as Decimal.RoundCeil.
+	RoundCeil
+	// RoundFloor rounds towards -infinity. Same as Decimal.RoundFloor.
+	RoundFloor
+	// RoundDown rounds towards zero. Same as Decimal.RoundDown.
+	RoundDown
+	// RoundUp rounds away from zero. Same as Decimal.RoundUp.
+	RoundUp
+)
+
+// optimized:
+// Add returns a + b, same as a.Add(b).
+// This is useful as function value, e.g. for folds / reduce.
+func Add(a, b Decimal) Decimal {
+	return a.Add(b)
+}
+
+// optimized:
+// AlignScale returns the max number of significant decimal places across ds,
+// and each of ds formatted with exactly that many places, e.g. to display a column.
+//
+// Example:
+//
+//	AlignScale([]Decimal{NewFromFloat(1.5), NewFromInt(2), NewFromFloat(0.125)})
+//	// output: 3, ["1.500", "2.000", "0.125"]
+func AlignScale(ds []Decimal) (places int32, aligned []string) {
+	for _, d := range ds {
+		if p := d.places(); p > places {
+			places = p
+		}
+	}
+
+	// d has at most places significant decimal places,
+	// so this is the same as StringFixed(places), without rounding.
+	aligned = make([]string, len(ds))
+	for i, d := range ds {
+		aligned[i] = d.StringWithMinPlaces(places)
+	}
+	return places, aligned
+}
+
 // optimized:
 // Avg returns the average value of the provided first and rest Decimals
 func Avg(first Decimal, rest ...Decimal) Decimal {
 	return Sum(first, rest...).Div(NewFromInt(int64(1 + len(rest))))
 }
 
+// optimized:
+// AvgSafe returns the average value of the provided Decimals, or an error if s is empty.
+//
+// Unlike Avg, it computes a running mean `mean += (x - mean) / i` instead of
+// summing all values first, so intermediate results stay close to the magnitude of
+// the inputs and remain optimized where the sum would not. Each step is rounded to
+// DivisionPrecision if the division is not exact, so the result may differ from Avg
+// in the last digits.
+func AvgSafe(s []Decimal) (Decimal, error) {
+	if len(s) == 0 {
+		return Zero, errors.New("can't compute average of empty slice")
+	}
+
+	mean := s[0]
+	for i := 1; i < len(s); i++ {
+		delta, _ := s[i].Sub(mean).DivInt(int64(i + 1))
+		mean = mean.Add(delta)
+	}
+	return mean, nil
+}
+
+// optimized:
+// Compare compares the numbers represented by a and b, same as a.Cmp(b).
+// This is useful as comparison func, e.g. `slices.SortFunc(xs, alpacadecimal.Compare)`.
+func Compare(a, b Decimal) int {
+	return a.Cmp(b)
+}
+
+// optimized:
+// Distinct returns the distinct values of ds by numeric equality, in order of first occurrence,
+// e.g. 1.0 and 1.00 are the same value, only the first one is kept.
+// It returns nil if ds is empty.
+func Distinct(ds []Decimal) []Decimal {
+	if len(ds) == 0 {
+		return nil
+	}
+
+	// canonical optimized values are keyed by fixed, the rest by their canonical string
+	seen := make(map[int64]struct{}, len(ds))
+	var seenFallback map[string]struct{}
+
+	result := make([]Decimal, 0, len(ds))
+	for _, d := range ds {
+		c := d.Canonical()
+		if c.fallback == nil {
+			if _, ok := seen[c.fixed]; ok {
+				continue
+			}
+			seen[c.fixed] = struct{}{}
+		} else {
+			key := c.fallback.String()
+			if _, ok := seenFallback[key]; ok {
+				continue
+			}
+			if seenFallback == nil {
+				seenFallback = make(map[string]struct{})
+			}
+			seenFallback[key] = struct{}{}
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// optimized:
+// Div returns a / b, same as a.Div(b).
+// This is useful as function value, e.g. for folds / reduce.
+func Div(a, b Decimal) Decimal {
+	return a.Div(b)
+}
+
+// optimized:
+// KahanSum returns the sum of s, Zero if s is empty.
+//
+// Addition of decimals is exact, so unlike floats there is no rounding error to compensate.
+// Instead of a compensation term, both parts of the sum are accumulated exactly in wide integers:
+// the fixed values in a 128-bit accumulator, and the coefficients of fallback values in a single
+// big.Int at the smallest exponent seen, which is rescaled in place when a smaller exponent comes up.
+// So unlike Sum, neither an intermediate sum out of the optimized range nor a fallback value
+// goes through decimal.Decimal.Add, which allocates several times per add, and the result is
+// optimized as long as the final sum is within the optimized range.
+func KahanSum(s []Decimal) Decimal {
+	var hi, lo uint64
+	var rest, pow, term big.Int
+	var restExp int32
+	hasRest := false
+
+	for _, d := range s {
+		if d.fallback != nil {
+			// Coefficient returns a copy, which is the only allocation per value unless rescaled by more than 10^18
+			c, exp := d.fallback.Coefficient(), d.fallback.Exponent()
+			switch {
+			case !hasRest:
+				rest.Set(c)
+				restExp = exp
+				hasRest = true
+				continue
+			case exp < restExp:
+				rest.Mul(&rest, pow10Big(&pow, restExp-exp))
+				restExp = exp
+			case exp > restExp:
+				c = term.Mul(c, pow10Big(&pow, exp-restExp))
+			}
+			rest.Add(&rest, c)
+			continue
+		}
+
+		// 128-bit two's complement add, sign extended from int64
+		var carry uint64
+		lo, carry = bits.Add64(lo, uint64(d.fixed), 0)
+		hi, _ = bits.Add64(hi, uint64(d.fixed>>63), carry)
+	}
+
+	var sum big.Int
+	if int64(hi) == int64(lo)>>63 {
+		// fits into int64
+		fixed := int64(lo)
+		if !hasRest && fixed >= minIntInFixed && fixed <= maxIntInFixed {
+			return Decimal{fixed: fixed}
+		}
+		sum.SetInt64(fixed)
+	} else {
+		sum.Lsh(sum.SetInt64(int64(hi)), 64)
+		sum.Add(&sum, term.SetUint64(lo))
+	}
+
+	reportFallback("KahanSum")
+	exp := int32(-precision)
+	if hasRest {
+		if restExp < exp {
+			sum.Mul(&sum, pow10Big(&pow, exp-restExp))
+			exp = restExp
+		} else if restExp > exp {
+			rest.Mul(&rest, pow10Big(&pow, restExp-exp))
+		}
+		sum.Add(&sum, &rest)
+	}
+	return optimizeFromDecimal(decimal.NewFromBigInt(&sum, exp))
+}
+
 // optimized:
 // Max returns the largest Decimal that was passed in the arguments.
 func Max(first Decimal, rest ...Decimal) Decimal {
@@ -111,6 +395,23 @@ func Max(first Decimal, rest ...Decimal) Decimal {
 	return result
 }
 
+// optimized:
+// MaxIndex returns the largest Decimal in ds and its index, the first one on ties.
+// It returns (Zero, -1) if ds is empty.
+func MaxIndex(ds []Decimal) (Decimal, int) {
+	if len(ds) == 0 {
+		return Zero, -1
+	}
+
+	index := 0
+	for i := 1; i < len(ds); i++ {
+		if ds[i].Cmp(ds[index]) > 0 {
+			index = i
+		}
+	}
+	return ds[index], index
+}
+
 // optimized:
 // Min returns the smallest Decimal that was passed in the arguments.
 func Min(first Decimal, rest ...Decimal) Decimal {
@@ -123,6 +424,30 @@ func Min(first Decimal, rest ...Decimal) Decimal {
 	return result
 }
 
+// optimized:
+// MinIndex returns the smallest Decimal in ds and its index, the first one on ties.
+// It returns (Zero, -1) if ds is empty.
+func MinIndex(ds []Decimal) (Decimal, int) {
+	if len(ds) == 0 {
+		return Zero, -1
+	}
+
+	index := 0
+	for i := 1; i < len(ds); i++ {
+		if ds[i].Cmp(ds[index]) < 0 {
+			index = i
+		}
+	}
+	return ds[index], index
+}
+
+// optimized:
+// Mul returns a * b, same as a.Mul(b).
+// This is useful as function value, e.g. for folds / reduce.
+func Mul(a, b Decimal) Decimal {
+	return a.Mul(b)
+}
+
 // optimized:
 // New returns a new fixed-point decimal, value * 10 ^ exp.
 func New(value int64, exp int32) Decimal {
@@ -139,32 +464,65 @@ func New(value int64, exp int32) Decimal {
 			}
 		}
 	}
+	reportFallback("New")
 	return newFromDecimal(decimal.New(value, exp))
 }
 
 // fallback:
 // NewFromBigInt returns a new Decimal from a big.Int, value * 10 ^ exp
 func NewFromBigInt(value *big.Int, exp int32) Decimal {
+	reportFallback("NewFromBigInt")
 	return newFromDecimal(decimal.NewFromBigInt(value, exp))
 }
 
+// optimized:
+// NewFromDecimal converts a decimal.Decimal to Decimal.
+// The result is optimized if d is within optimized range with up to 12 precision.
+func NewFromDecimal(d decimal.Decimal) Decimal {
+	if fixed, ok := fixedFromDecimal(d); ok {
+		return Decimal{fixed: fixed}
+	}
+
+	reportFallback("NewFromDecimal")
+	return newFromDecimal(d)
+}
+
 // optimized:
 // NewFromFloat converts a float64 to Decimal.
 //
 // NOTE: this will panic on NaN, +/-inf
 func NewFromFloat(f float64) Decimal {
-	picoFloat := f * float64(scale)
-	picoInt64 := int64(picoFloat)
-
-	// check if it's within range and is whole number
-	// integer overflow is accounted for via the `picoFloat == float64(picoInt64)` check
-	if picoInt64 >= minIntInFixed && picoInt64 <= maxIntInFixed && picoFloat == float64(picoInt64) {
-		return Decimal{fixed: picoInt64}
+	if fixed, ok := fixedFromFloat(f); ok {
+		return Decimal{fixed: fixed}
 	}
 
+	reportFallback("NewFromFloat")
 	return newFromDecimal(decimal.NewFromFloat(f))
 }
 
+// optimized:
+// NewFromFloatExact converts a float64 to Decimal, returning an error
+// instead of falling back when the float can not be represented exactly
+// with 12 precision within the optimized range.
+//
+// Unlike NewFromFloat, this returns an error on NaN, +/-inf.
+func NewFromFloatExact(f float64) (Decimal, error) {
+	if fixed, ok := fixedFromFloat(f); ok {
+		return Decimal{fixed: fixed}, nil
+	}
+	return Zero, fmt.Errorf("can't convert %v to Decimal exactly", f)
+}
+
+// optimized:
+// NewFromFloatSafe converts a float64 to Decimal, same as NewFromFloat,
+// but returns an error on NaN, +/-inf instead of panicking, e.g. for untrusted inputs.
+func NewFromFloatSafe(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Zero, fmt.Errorf("can't convert %v to Decimal", f)
+	}
+	return NewFromFloat(f), nil
+}
+
 // fallback:
 // NewFromFloat32 converts a float32 to Decimal.
 //
@@ -177,6 +535,7 @@ func NewFromFloat(f float64) Decimal {
 //
 // NOTE: this will panic on NaN, +/-inf
 func NewFromFloat32(f float32) Decimal {
+	reportFallback("NewFromFloat32")
 	return newFromDecimal(decimal.NewFromFloat32(f))
 }
 
@@ -209,15 +568,38 @@ func NewFromInt(x int64) Decimal {
 	if x >= minInt && x <= maxInt {
 		return Decimal{fixed: x * scale}
 	}
+	return newFromIntFallback(x)
+}
+
+// newFromIntFallback is split out of NewFromInt to keep NewFromInt small enough to be inlined.
+func newFromIntFallback(x int64) Decimal {
+	reportFallback("NewFromInt")
 	return newFromDecimal(decimal.NewFromInt(x))
 }
 
+// optimized:
+// NewFromIntExact converts a int64 to Decimal, returning ErrOutOfRange
+// instead of falling back when x is out of the optimized range.
+func NewFromIntExact(x int64) (Decimal, error) {
+	if x >= minInt && x <= maxInt {
+		return Decimal{fixed: x * scale}, nil
+	}
+	return Zero, ErrOutOfRange
+}
+
 // optimized:
 // NewFromInt32 converts a int32 to Decimal.
 func NewFromInt32(value int32) Decimal {
 	return NewFromInt(int64(value))
 }
 
+// optimized:
+// NewFromJSONNumber returns a new Decimal from a json.Number,
+// e.g. of values decoded with json.Decoder.UseNumber, same as NewFromString(n.String()).
+func NewFromJSONNumber(n json.Number) (Decimal, error) {
+	return NewFromString(string(n))
+}
+
 // optimized:
 // NewFromString returns a new Decimal from a string representation.
 func NewFromString(value string) (Decimal, error) {
@@ -226,6 +608,87 @@ func NewFromString(value string) (Decimal, error) {
 	}
 
 	// fallback
+	reportFallback("NewFromString")
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return Zero, err
+	}
+	return newFromDecimal(d), nil
+}
+
+// optimized:
+// NewFromStringCached is same as NewFromString, but memoizes successful parses
+// for inputs that repeat a lot, e.g. "0.00", "1.00".
+//
+// The cache holds up to 4096 distinct inputs, further inputs are parsed
+// but not memoized until ClearParseCache is called.
+//
+// NOTE: parsing short inputs in optimized range is about as fast as the cache lookup
+// (see BenchmarkNewFromStringCached), so this mostly pays off for long or fallback inputs.
+func NewFromStringCached(value string) (Decimal, error) {
+	c := parseCache.Load().(*stringParseCache)
+	if d, ok := c.m.Load(value); ok {
+		return d.(Decimal), nil
+	}
+
+	d, err := NewFromString(value)
+	if err != nil {
+		return Zero, err
+	}
+
+	if atomic.LoadInt64(&c.n) < parseCacheSize && atomic.AddInt64(&c.n, 1) <= parseCacheSize {
+		c.m.Store(value, d)
+	}
+	return d, nil
+}
+
+// ClearParseCache clears the cache used by NewFromStringCached.
+func ClearParseCache() {
+	parseCache.Store(&stringParseCache{})
+}
+
+// optimized:
+// ParseAll parses a slice of string representations in one pass.
+//
+// The returned errors slice has the same length as values, with nil entries for successes.
+// Values that fail to parse are set to Zero. See ParseAllStopOnError to stop at the first error.
+func ParseAll(values []string) ([]Decimal, []error) {
+	result := make([]Decimal, len(values))
+	errs := make([]error, len(values))
+
+	for i, v := range values {
+		if fixed, ok := parseFixed(v); ok {
+			result[i] = Decimal{fixed: fixed}
+			continue
+		}
+		result[i], errs[i] = parseAllFallback(v)
+	}
+	return result, errs
+}
+
+// optimized:
+// ParseAllStopOnError is same as ParseAll, but stops at the first value that fails to parse.
+// It returns the values parsed before it, and an error with its index.
+func ParseAllStopOnError(values []string) ([]Decimal, error) {
+	result := make([]Decimal, len(values))
+
+	for i, v := range values {
+		if fixed, ok := parseFixed(v); ok {
+			result[i] = Decimal{fixed: fixed}
+			continue
+		}
+
+		d, err := parseAllFallback(v)
+		if err != nil {
+			return result[:i], fmt.Errorf("can't parse value %d: %w", i, err)
+		}
+		result[i] = d
+	}
+	return result, nil
+}
+
+func parseAllFallback(value string) (Decimal, error) {
+	reportFallback("ParseAll")
 	d, err := decimal.NewFromString(value)
 	if err != nil {
 		return Zero, err
@@ -233,6 +696,85 @@ func NewFromString(value string) (Decimal, error) {
 	return newFromDecimal(d), nil
 }
 
+// optimized:
+// ParsePrefix parses the decimal at the start of b and returns it with the number of bytes consumed,
+// e.g. for decimals embedded in a larger buffer of a framed protocol, without copying b.
+//
+// The token is an optional leading '+' or '-', followed by digits with at most one '.',
+// it ends at the first byte which can't continue it, e.g. "1.5;2" => 1.5, 3 and "-2.5.1" => -2.5, 4.
+// Exponents are not part of the token, e.g. "1e3" => 1, 1.
+// It returns an error if the token has no digits.
+func ParsePrefix(b []byte) (Decimal, int, error) {
+	n := 0
+	if n < len(b) && (b[n] == '+' || b[n] == '-') {
+		n++
+	}
+
+	hasDigits, hasDot := false, false
+	for ; n < len(b); n++ {
+		c := b[n]
+		if '0' <= c && c <= '9' {
+			hasDigits = true
+		} else if c == '.' && !hasDot {
+			hasDot = true
+		} else {
+			break
+		}
+	}
+	if !hasDigits {
+		return Zero, 0, fmt.Errorf("can't parse decimal from %q", b[:n])
+	}
+
+	if fixed, ok := parseFixed(b[:n]); ok {
+		return Decimal{fixed: fixed}, n, nil
+	}
+
+	// fallback
+	reportFallback("ParsePrefix")
+	d, err := decimal.NewFromString(string(b[:n]))
+	if err != nil {
+		return Zero, 0, err
+	}
+	return newFromDecimal(d), n, nil
+}
+
+// optimized:
+// PercentChange returns the relative change (to - from) / from as a ratio, e.g. 0.25 for 100 to 125,
+// or an error if from is zero.
+// Like Div, the result is rounded to DivisionPrecision if the division is not exact.
+func PercentChange(from, to Decimal) (Decimal, error) {
+	if from.IsZero() {
+		return Zero, errors.New("can't compute percent change from zero")
+	}
+	return to.Sub(from).Div(from), nil
+}
+
+// optimized:
+// PercentChangePct is PercentChange multiplied by 100, e.g. 25 for 100 to 125.
+func PercentChangePct(from, to Decimal) (Decimal, error) {
+	d, err := PercentChange(from, to)
+	if err != nil {
+		return Zero, err
+	}
+	return d.MulPow10(2), nil
+}
+
+// optimized:
+// Pow10 returns 10^n, e.g. as scaling factor. It is optimized for n within [-12, 6],
+// same as New(1, n) otherwise.
+func Pow10(n int32) Decimal {
+	if n >= -precision && n <= 6 {
+		return Decimal{fixed: pow10Table[n+precision]}
+	}
+	return pow10Fallback(n)
+}
+
+// pow10Fallback is split out of Pow10 to keep Pow10 small enough to be inlined.
+func pow10Fallback(n int32) Decimal {
+	reportFallback("Pow10")
+	return newFromDecimal(decimal.New(1, n))
+}
+
 // optimized:
 // RequireFromString returns a new Decimal from a string representation
 // or panics if NewFromString would have returned an error.
@@ -244,6 +786,13 @@ func RequireFromString(value string) Decimal {
 	return d
 }
 
+// optimized:
+// Sub returns a - b, same as a.Sub(b).
+// This is useful as function value, e.g. for folds / reduce.
+func Sub(a, b Decimal) Decimal {
+	return a.Sub(b)
+}
+
 // optimized:
 // Sum returns the combined total of the provided first and rest Decimals
 func Sum(first Decimal, rest ...Decimal) Decimal {
@@ -254,21 +803,85 @@ func Sum(first Decimal, rest ...Decimal) Decimal {
 	return result
 }
 
+// optimized:
+// ToFloat64Slice converts ds to float64 values in one pass, e.g. for charting libraries.
+//
+// Optimized values are converted as float64(fixed) / 10^12, without going through decimal.Decimal.
+// This is exact to the nearest float64 up to 2^53 / 10^12 (about 9007), above that the result
+// may differ from InexactFloat64 in the last bit.
+func ToFloat64Slice(ds []Decimal) []float64 {
+	fs := make([]float64, len(ds))
+	for i, d := range ds {
+		if d.fallback == nil {
+			fs[i] = float64(d.fixed) / scale
+		} else {
+			fs[i] = d.fallback.InexactFloat64()
+		}
+	}
+	return fs
+}
+
+// optimized:
+// WeightedAvg returns sum(values[i] * weights[i]) / sum(weights), e.g. the average price of fills
+// weighted by quantity, or an error if the lengths differ or the total weight is zero.
+//
+// Products are exact, and both sums are computed with KahanSum, so large intermediate sums
+// do not make the result fallback. Like Div, the result is rounded to DivisionPrecision
+// if the division is not exact.
+func WeightedAvg(values, weights []Decimal) (Decimal, error) {
+	if len(values) != len(weights) {
+		return Zero, fmt.Errorf("can't compute weighted average of %d values with %d weights", len(values), len(weights))
+	}
+
+	products := make([]Decimal, len(values))
+	for i, v := range values {
+		products[i] = v.Mul(weights[i])
+	}
+
+	total := KahanSum(weights)
+	if total.IsZero() {
+		return Zero, errors.New("can't compute weighted average with zero total weight")
+	}
+	return KahanSum(products).Div(total), nil
+}
+
 // optimized:
 // Abs returns the absolute value of the decimal.
 func (d Decimal) Abs() Decimal {
 	if d.fallback == nil {
 		if d.fixed >= 0 {
 			return d
-		} else {
+		} else if d.fixed >= minIntInFixed {
+			// guard against malformed fixed (e.g. math.MinInt64), where negation overflows
 			return Decimal{fixed: -d.fixed}
 		}
 	}
-	return newFromDecimal(d.fallback.Abs())
+	reportFallback("Abs")
+	return newFromDecimal(d.asFallback().Abs())
+}
+
+// optimized:
+// AbsDiff returns |d - d2|, e.g. the spread between two prices, same as d.Sub(d2).Abs().
+func (d Decimal) AbsDiff(d2 Decimal) Decimal {
+	if d.fallback == nil && d2.fallback == nil {
+		// the difference of two in-range values always fits in uint64
+		var diff uint64
+		if d.fixed >= d2.fixed {
+			diff = uint64(d.fixed) - uint64(d2.fixed)
+		} else {
+			diff = uint64(d2.fixed) - uint64(d.fixed)
+		}
+		if diff <= uint64(maxIntInFixed) {
+			return Decimal{fixed: int64(diff)}
+		}
+	}
+	reportFallback("AbsDiff")
+	return newFromDecimal(d.asFallback().Sub(d2.asFallback()).Abs())
 }
 
 // optimized:
 // Add returns d + d2.
+// The result is optimized if it is representable, even if d or d2 is not, e.g. 1e-13 + -1e-13.
 func (d Decimal) Add(d2 Decimal) Decimal {
 	// if result of add is not overflow,
 	// we can keep result as optimized format as well.
@@ -287,7 +900,56 @@ func (d Decimal) Add(d2 Decimal) Decimal {
 		}
 	}
 
-	return newFromDecimal(d.asFallback().Add(d2.asFallback()))
+	reportFallback("Add")
+	return sumFromDecimal(d.asFallback().Add(d2.asFallback()), d, d2)
+}
+
+// optimized:
+// AddExact returns d + d2, or ErrOutOfRange if the result can not be
+// represented by the optimized fixed format.
+func (d Decimal) AddExact(d2 Decimal) (Decimal, error) {
+	return exact(d.Add(d2))
+}
+
+// optimized:
+// AppendFixed appends the same as StringFixed(places) to b and returns the extended buffer,
+// without allocation for optimized d with places within [0, 12], e.g. for a CSV writer.
+func (d Decimal) AppendFixed(b []byte, places int32) []byte {
+	if r, ok := d.appendStringFixed(b, places); ok {
+		return r
+	}
+	return append(b, d.asFallback().StringFixed(places)...)
+}
+
+// appendStringFixed is the optimized path of AppendFixed and StringFixed,
+// it returns false if d has to fallback.
+func (d Decimal) appendStringFixed(b []byte, places int32) ([]byte, bool) {
+	if d.fallback != nil || places > precision || int(precision-places) >= len(pow10Table) {
+		return nil, false
+	}
+	if places >= 0 {
+		// pow10Table[precision-places] divides scale, and so divides maxIntInFixed,
+		// which means rounding away from zero can not overflow.
+		return appendFixed(b, roundFixed(d.fixed, pow10Table[precision-places], RoundHalfAwayFromZero), int(places)), true
+	}
+
+	// negative places round integer digits, e.g. 1234 => "1200" with places -2,
+	// guard against overflow of rounding away from zero.
+	s := pow10Table[precision-places]
+	if d.fixed > math.MaxInt64-s || d.fixed < -(math.MaxInt64-s) {
+		return nil, false
+	}
+	return appendFixed(b, roundFixed(d.fixed, s, RoundHalfAwayFromZero), 0), true
+}
+
+// optimized:
+// AppendText implements the encoding.TextAppender interface,
+// it appends the same as MarshalText to b, without allocation for optimized d.
+func (d Decimal) AppendText(b []byte) ([]byte, error) {
+	if d.fallback == nil {
+		return appendFixedString(b, d.fixed, 0), nil
+	}
+	return append(b, d.fallback.String()...), nil
 }
 
 // fallback:
@@ -308,6 +970,52 @@ func (d Decimal) BigInt() *big.Int {
 	return d.asFallback().BigInt()
 }
 
+// optimized:
+// Canonical returns d in a canonical form, so that equal numbers have the same
+// representation, i.e. the same Coefficient(), Exponent() and IsOptimized().
+// The result is optimized when d is representable, otherwise trailing zeros
+// of the coefficient are removed, e.g. 1.50 (150 * 10^-2) becomes 15 * 10^-1.
+//
+// NOTE: String() is already canonical for all values, i.e. it never has trailing zeros
+// after the decimal point, Canonical is only needed for the exponent based APIs.
+func (d Decimal) Canonical() Decimal {
+	if d.fallback == nil {
+		return d
+	}
+	if fixed, ok := fixedFromDecimal(*d.fallback); ok {
+		return Decimal{fixed: fixed}
+	}
+
+	// not zero, which is representable
+	c, exp := trimTrailingZeros(d.fallback.Coefficient(), d.fallback.Exponent())
+	return newFromDecimal(decimal.NewFromBigInt(c, exp))
+}
+
+// optimized:
+// CanonicalCoefficient returns the coefficient and exponent of d with trailing zeros
+// of the coefficient removed, e.g. (15, -1) for 1.50, and (0, 0) for zero.
+//
+// Unlike Coefficient() and Exponent(), which are scaled by 10^-12 for optimized values,
+// the result is the same for equal numbers of Decimal and decimal.Decimal.
+func (d Decimal) CanonicalCoefficient() (*big.Int, int32) {
+	if d.fallback == nil {
+		if d.fixed == 0 {
+			return new(big.Int), 0
+		}
+		c, exp := d.fixed, int32(-precision)
+		for c%10 == 0 {
+			c /= 10
+			exp++
+		}
+		return big.NewInt(c), exp
+	}
+
+	if d.fallback.Sign() == 0 {
+		return new(big.Int), 0
+	}
+	return trimTrailingZeros(d.fallback.Coefficient(), d.fallback.Exponent())
+}
+
 // optimized:
 // Ceil returns the nearest integer value greater than or equal to d.
 func (d Decimal) Ceil() Decimal {
@@ -321,7 +1029,8 @@ func (d Decimal) Ceil() Decimal {
 		}
 		return Decimal{fixed: d.fixed - m}
 	}
-	return newFromDecimal(d.asFallback().Ceil())
+	reportFallback("Ceil")
+	return optimizeFromDecimal(d.asFallback().Ceil())
 }
 
 // optimized:
@@ -341,8 +1050,26 @@ func (d Decimal) Cmp(d2 Decimal) int {
 			return 1
 		}
 	}
-	return d.asFallback().Cmp(d2.asFallback())
-}
+
+	// mixed case, same as Equal, compare at the exponent of the fallback value to avoid rescaling it.
+	if d.fallback == nil {
+		return cmpFixed(d.fixed, *d2.fallback)
+	}
+	if d2.fallback == nil {
+		return -cmpFixed(d2.fixed, *d.fallback)
+	}
+	return d.fallback.Cmp(*d2.fallback)
+}
+
+// optimized:
+// CmpDecimal compares d with a decimal.Decimal, same as d.Cmp(NewFromDecimal(d2)),
+// without converting d2.
+func (d Decimal) CmpDecimal(d2 decimal.Decimal) int {
+	if d.fallback == nil {
+		return cmpFixed(d.fixed, d2)
+	}
+	return d.fallback.Cmp(d2)
+}
 
 // optimized:
 // Coefficient returns the coefficient of the decimal. It is scaled by 10^Exponent()
@@ -362,6 +1089,23 @@ func (d Decimal) CoefficientInt64() int64 {
 	return d.asFallback().CoefficientInt64()
 }
 
+// optimized:
+// CoefficientText returns the coefficient of the decimal in the given base,
+// which together with Exponent() reconstructs the exact value.
+// e.g. NewFromInt(1).CoefficientText(16) returns "e8d4a51000" with Exponent() -12.
+//
+// NOTE: this will panic if base is not within [2, 36].
+func (d Decimal) CoefficientText(base int) string {
+	if d.fallback == nil {
+		return strconv.FormatInt(d.fixed, base)
+	}
+	if base < 2 || base > 36 {
+		// keep the same range as strconv, while big.Int supports up to 62.
+		panic(fmt.Sprintf("invalid base %d", base))
+	}
+	return d.fallback.Coefficient().Text(base)
+}
+
 // optimized:
 // Copy returns a copy of decimal with the same value and exponent, but a different pointer to value.
 func (d Decimal) Copy() Decimal {
@@ -387,24 +1131,188 @@ func (d Decimal) Div(d2 Decimal) Decimal {
 			return Decimal{fixed: fixed}
 		}
 	}
+	reportFallback("Div")
 	return d.DivRound(d2, int32(DivisionPrecision))
 }
 
+// optimized:
+// DivInt returns d / n and whether the quotient is exact.
+// If it doesn't divide exactly, the quotient will be the same as
+// d.Div(NewFromInt(n)) and exact will be false.
+// DivInt returns (Zero, false) when n is 0.
+func (d Decimal) DivInt(n int64) (Decimal, bool) {
+	if n == 0 {
+		return Zero, false
+	}
+	if d.fallback == nil && d.fixed%n == 0 {
+		return Decimal{fixed: d.fixed / n}, true
+	}
+	q := d.Div(NewFromInt(n))
+	if q.fallback != nil {
+		// Div already reported the fallback, so check without MulInt reporting it again
+		return q, q.fallback.Mul(decimal.NewFromInt(n)).Equal(d.asFallback())
+	}
+	return q, q.MulInt(n).Equal(d)
+}
+
+// optimized:
+// DivMod returns the integer quotient q and the remainder r of d / d2, such that
+//
+//	d = d2 * q + r, q an integer
+//	0 <= r < abs(d2) if d >= 0
+//	0 >= r > -abs(d2) if d < 0
+//
+// q is truncated toward zero and r has the sign of d, same as Mod.
+//
+// NOTE: this differs from Python's divmod for negative operands, which floors q and gives r the sign of d2,
+// e.g. -7 DivMod 2 is (-3, -1) here, but divmod(-7, 2) is (-4, 1) in Python.
+//
+// DivMod panics if d2 is zero.
+func (d Decimal) DivMod(d2 Decimal) (Decimal, Decimal) {
+	if d.fallback == nil && d2.fallback == nil && d2.fixed != 0 {
+		return NewFromInt(d.fixed / d2.fixed), Decimal{fixed: d.fixed % d2.fixed}
+	}
+	reportFallback("DivMod")
+	q, r := d.asFallback().QuoRem(d2.asFallback(), 0)
+	return newFromDecimal(q), newFromDecimal(r)
+}
+
 // fallback:
 // DivRound divides and rounds to a given precision
 func (d Decimal) DivRound(d2 Decimal, precision int32) Decimal {
 	return newFromDecimal(d.asFallback().DivRound(d2.asFallback(), precision))
 }
 
+// optimized:
+// DivPow10 returns d / 10^n, e.g. to convert cents to dollars, same as Shift(-n).
+//
+// The result is always exact, i.e. it is never rounded: if it has more than 12 decimal places
+// it falls back to decimal.Decimal, use Round / RoundBank afterwards to limit the places.
+func (d Decimal) DivPow10(n int32) Decimal {
+	return d.MulPow10(-n)
+}
+
+// optimized:
+// DivRoundBank divides and rounds to places decimal places with banker's rounding,
+// i.e. half to even. Unlike d.Div(d2).RoundBank(places), the exact quotient is rounded,
+// so there is no double rounding.
+func (d Decimal) DivRoundBank(d2 Decimal, places int32) Decimal {
+	if d.fallback == nil && d2.fallback == nil && d2.fixed != 0 && places >= 0 && places <= precision {
+		if fixed, ok := divRound(d.fixed, d2.fixed, int(places), RoundHalfEven); ok {
+			return Decimal{fixed: fixed}
+		}
+	}
+
+	// same as decimal.Decimal.DivRound, except for the tie
+	reportFallback("DivRoundBank")
+	dd, dd2 := d.asFallback(), d2.asFallback()
+	q, r := dd.QuoRem(dd2, places)
+	c := r.Abs().Mul(decimal.NewFromInt(2)).Shift(places).Cmp(dd2.Abs())
+	if c < 0 || (c == 0 && q.Shift(places).BigInt().Bit(0) == 0) {
+		return newFromDecimal(q)
+	}
+	if dd.Sign()*dd2.Sign() < 0 {
+		return newFromDecimal(q.Sub(decimal.New(1, -places)))
+	}
+	return newFromDecimal(q.Add(decimal.New(1, -places)))
+}
+
+// optimized:
+// DivToScale returns d / d2 rounded with the given rounding mode to a multiple of 10^exp,
+// e.g. exp -2 for cents, or an error if d2 is zero.
+//
+// Unlike d.Div(d2).RoundWithMode(-exp, mode), the exact quotient is rounded,
+// so there is no double rounding through the DivisionPrecision digits of Div.
+// The result stays optimized if d and d2 are optimized, exp is within [-12, 0]
+// and the quotient is within the optimized range.
+func (d Decimal) DivToScale(d2 Decimal, exp int32, mode RoundingMode) (Decimal, error) {
+	if d2.IsZero() {
+		return Zero, fmt.Errorf("can't divide %s by zero", d)
+	}
+
+	if d.fallback == nil && d2.fallback == nil && exp >= -precision && exp <= 0 {
+		if fixed, ok := divRound(d.fixed, d2.fixed, int(-exp), mode); ok {
+			return Decimal{fixed: fixed}, nil
+		}
+	}
+
+	reportFallback("DivToScale")
+	dd, dd2 := d.asFallback(), d2.asFallback()
+	q, r := dd.QuoRem(dd2, -exp)
+	if r.IsZero() {
+		return optimizeFromDecimal(q), nil
+	}
+
+	negative := dd.Sign()*dd2.Sign() < 0
+	c := r.Abs().Mul(decimal.NewFromInt(2)).Shift(-exp).Cmp(dd2.Abs())
+	if !roundAwayFromZero(mode, negative, c, q.Shift(-exp).BigInt().Bit(0) == 1) {
+		return optimizeFromDecimal(q), nil
+	}
+	if negative {
+		return optimizeFromDecimal(q.Sub(decimal.New(1, exp))), nil
+	}
+	return optimizeFromDecimal(q.Add(decimal.New(1, exp))), nil
+}
+
 // optimized:
 // Equal returns whether the numbers represented by d and d2 are equal.
 func (d Decimal) Equal(d2 Decimal) bool {
 	if d.fallback == nil && d2.fallback == nil {
 		return d.fixed == d2.fixed
 	}
+
+	// mixed case, compare at the exponent of the fallback value to avoid rescaling it.
+	if d.fallback == nil {
+		return equalFixed(*d2.fallback, d.fixed)
+	}
+	if d2.fallback == nil {
+		return equalFixed(*d.fallback, d2.fixed)
+	}
 	return d.asFallback().Equal(d2.asFallback())
 }
 
+// optimized:
+// EqualDecimal returns whether d equals to a decimal.Decimal, same as d.Equal(NewFromDecimal(d2)),
+// without converting d2.
+func (d Decimal) EqualDecimal(d2 decimal.Decimal) bool {
+	if d.fallback == nil {
+		return equalFixed(d2, d.fixed)
+	}
+	return d.fallback.Equal(d2)
+}
+
+// optimized:
+// EqualRelative returns whether |d - d2| <= relTol * max(|d|, |d2|),
+// e.g. d.EqualRelative(d2, RequireFromString("0.0001")) for within 0.01%.
+// This is handy to compare computed values without an absolute epsilon, which depends on magnitude.
+func (d Decimal) EqualRelative(d2, relTol Decimal) bool {
+	if d.fallback == nil && d2.fallback == nil && relTol.fallback == nil && relTol.fixed >= 0 {
+		// |d - d2| * scale <= relTol * max(|d|, |d2|) in 128 bits, where everything is in fixed
+		var diff uint64
+		if d.fixed >= d2.fixed {
+			diff = uint64(d.fixed) - uint64(d2.fixed)
+		} else {
+			diff = uint64(d2.fixed) - uint64(d.fixed)
+		}
+
+		m := absFixed(d.fixed)
+		if m2 := absFixed(d2.fixed); m2 > m {
+			m = m2
+		}
+
+		lhi, llo := bits.Mul64(diff, scale)
+		rhi, rlo := bits.Mul64(uint64(relTol.fixed), m)
+		return lhi < rhi || (lhi == rhi && llo <= rlo)
+	}
+
+	x, y := d.asFallback(), d2.asFallback()
+	m := x.Abs()
+	if yy := y.Abs(); yy.GreaterThan(m) {
+		m = yy
+	}
+	return x.Sub(y).Abs().LessThanOrEqual(relTol.asFallback().Mul(m))
+}
+
 // fallback:
 // Equals is deprecated, please use Equal method instead
 func (d Decimal) Equals(d2 Decimal) bool {
@@ -443,6 +1351,24 @@ func (d Decimal) Exponent() int32 {
 	return d.fallback.Exponent()
 }
 
+// optimized:
+// Float32 returns the nearest float32 value for d and a bool indicating
+// whether f represents d exactly.
+//
+// Unlike `float32(d.InexactFloat64())`, this rounds only once.
+func (d Decimal) Float32() (f float32, exact bool) {
+	if d.fallback == nil {
+		// whole numbers within 2^24 are exact float32
+		if d.fixed%scale == 0 {
+			if i := d.fixed / scale; i >= -1<<24 && i <= 1<<24 {
+				return float32(i), true
+			}
+		}
+		return new(big.Rat).SetFrac64(d.fixed, scale).Float32()
+	}
+	return d.fallback.Rat().Float32()
+}
+
 // fallback:
 // Float64 returns the nearest float64 value for d and a bool indicating
 // whether f represents d exactly.
@@ -450,6 +1376,22 @@ func (d Decimal) Float64() (f float64, exact bool) {
 	return d.asFallback().Float64()
 }
 
+// optimized:
+// FMA returns d * mul + add, e.g. base + qty * price as price.FMA(qty, base).
+//
+// Unlike d.Mul(mul).Add(add), the intermediate product is not range checked,
+// so the result stays optimized as long as d * mul has at most 12 decimal places
+// and the final result is within the optimized range.
+func (d Decimal) FMA(mul, add Decimal) Decimal {
+	if d.fallback == nil && mul.fallback == nil && add.fallback == nil {
+		if fixed, ok := fma(d.fixed, mul.fixed, add.fixed); ok {
+			return Decimal{fixed: fixed}
+		}
+	}
+	reportFallback("FMA")
+	return newFromDecimal(d.asFallback().Mul(mul.asFallback()).Add(add.asFallback()))
+}
+
 // optimized:
 // Floor returns the nearest integer value less than or equal to d.
 func (d Decimal) Floor() Decimal {
@@ -463,15 +1405,18 @@ func (d Decimal) Floor() Decimal {
 		}
 		return Decimal{fixed: d.fixed - m - scale}
 	}
-	return newFromDecimal(d.asFallback().Floor())
+	reportFallback("Floor")
+	return optimizeFromDecimal(d.asFallback().Floor())
 }
 
-// fallback: (can be optimized if needed)
+// optimized:
+// GobDecode implements the gob.GobDecoder interface, same as UnmarshalBinary.
 func (d *Decimal) GobDecode(data []byte) error {
 	return d.UnmarshalBinary(data)
 }
 
-// fallback: (can be optimized if needed)
+// optimized:
+// GobEncode implements the gob.GobEncoder interface, same as MarshalBinary.
 func (d Decimal) GobEncode() ([]byte, error) {
 	return d.MarshalBinary()
 }
@@ -510,6 +1455,25 @@ func (d Decimal) IntPart() int64 {
 	return d.fallback.IntPart()
 }
 
+// optimized:
+// IsApproxInteger returns true when d is within tolerance of its nearest integer,
+// e.g. 2.9999999999 with tolerance 1e-9, to detect float artifacts before storing a value.
+//
+// It returns false if tolerance is negative, and is the same as IsInteger if tolerance is zero.
+func (d Decimal) IsApproxInteger(tolerance Decimal) bool {
+	if d.fallback == nil && tolerance.fallback == nil {
+		m := d.fixed % scale
+		if m < 0 {
+			m = -m
+		}
+		if m > scale/2 {
+			m = scale - m
+		}
+		return m <= tolerance.fixed
+	}
+	return d.Sub(d.Round(0)).Abs().LessThanOrEqual(tolerance)
+}
+
 // optimized:
 // IsInteger returns true when decimal can be represented as an integer value, otherwise, it returns false.
 func (d Decimal) IsInteger() bool {
@@ -519,6 +1483,21 @@ func (d Decimal) IsInteger() bool {
 	return d.fallback.IsInteger()
 }
 
+// optimized:
+// IsMultipleOf returns true if d is an integer multiple of step, e.g. to validate
+// an order quantity against the lot size or a price against the tick size.
+//
+// It returns false if step is zero.
+func (d Decimal) IsMultipleOf(step Decimal) bool {
+	if d.fallback == nil && step.fallback == nil {
+		return step.fixed != 0 && d.fixed%step.fixed == 0
+	}
+	if step.IsZero() {
+		return false
+	}
+	return d.asFallback().Mod(step.asFallback()).IsZero()
+}
+
 // optimized:
 // IsNegative return
 //
@@ -532,6 +1511,54 @@ func (d Decimal) IsNegative() bool {
 	return d.fallback.IsNegative()
 }
 
+// optimized:
+// IsNegativeOne return
+//
+//	true if d == -1
+//	false if d != -1
+func (d Decimal) IsNegativeOne() bool {
+	if d.fallback == nil {
+		return d.fixed == -scale
+	}
+	return d.fallback.Equal(fallbackNegOne)
+}
+
+// optimized:
+// IsNonNegative return
+//
+//	true if d >= 0
+//	false if d < 0
+func (d Decimal) IsNonNegative() bool {
+	if d.fallback == nil {
+		return d.fixed >= 0
+	}
+	return d.fallback.Sign() >= 0
+}
+
+// optimized:
+// IsNonPositive return
+//
+//	true if d <= 0
+//	false if d > 0
+func (d Decimal) IsNonPositive() bool {
+	if d.fallback == nil {
+		return d.fixed <= 0
+	}
+	return d.fallback.Sign() <= 0
+}
+
+// optimized:
+// IsOne return
+//
+//	true if d == 1
+//	false if d != 1
+func (d Decimal) IsOne() bool {
+	if d.fallback == nil {
+		return d.fixed == scale
+	}
+	return d.fallback.Equal(fallbackOne)
+}
+
 // optimized:
 // IsPositive return
 //
@@ -576,21 +1603,59 @@ func (d Decimal) LessThanOrEqual(d2 Decimal) bool {
 	return d.asFallback().LessThanOrEqual(d2.asFallback())
 }
 
-// fallback:
+// optimized:
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
+// It writes the decimal.Decimal binary format, or the compact format if MarshalBinaryCompact is set.
 func (d Decimal) MarshalBinary() (data []byte, err error) {
-	return d.asFallback().MarshalBinary()
+	if !MarshalBinaryCompact {
+		return d.asFallback().MarshalBinary()
+	}
+
+	if d.fallback == nil {
+		data = make([]byte, 9)
+		data[0] = binaryVersionFixed
+		binary.BigEndian.PutUint64(data[1:], uint64(d.fixed))
+		return data, nil
+	}
+
+	v, err := d.fallback.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{binaryVersionFallback}, v...), nil
 }
 
 // optimized:
+// MarshalJSON implements the json.Marshaler interface.
+//
+// NOTE: a decimal can't be NaN or Inf, so String() is always a plain number
+// like "-123.456". It is still checked here so that a change in formatting
+// can never produce malformed JSON silently.
 func (d Decimal) MarshalJSON() ([]byte, error) {
-	var str string
-	if MarshalJSONWithoutQuotes {
-		str = d.String()
+	// `"-9223372.000000000001"` => 23 bytes
+	return d.appendJSON(make([]byte, 0, 23))
+}
+
+// appendJSON appends the JSON encoding of d to b, shared by json v1 and v2 marshaling.
+func (d Decimal) appendJSON(b []byte) ([]byte, error) {
+	if !MarshalJSONWithoutQuotes {
+		b = append(b, '"')
+	}
+
+	start := len(b)
+	if d.fallback == nil {
+		b = appendFixedString(b, d.fixed, 0)
 	} else {
-		str = "\"" + d.String() + "\""
+		b = append(b, d.fallback.String()...)
+	}
+	if !isPlainNumber(b[start:]) {
+		return nil, fmt.Errorf("can't marshal decimal %q to json", b[start:])
+	}
+
+	if !MarshalJSONWithoutQuotes {
+		b = append(b, '"')
 	}
-	return []byte(str), nil
+	return b, nil
 }
 
 // optimized:
@@ -611,16 +1676,110 @@ func (d Decimal) Mul(d2 Decimal) Decimal {
 			return Decimal{fixed: fixed}
 		}
 	}
+	reportFallback("Mul")
 	return newFromDecimal(d.asFallback().Mul(d2.asFallback()))
 }
 
+// optimized:
+// MulRound returns d * d2 rounded to places decimal places, same as d.Mul(d2).Round(places),
+// but without the intermediate high precision product, so the result stays optimized when it fits.
+func (d Decimal) MulRound(d2 Decimal, places int32) Decimal {
+	if d.fallback == nil && d2.fallback == nil && places >= 0 {
+		if places > precision {
+			if fixed, ok := mul(d.fixed, d2.fixed); ok {
+				// exact product, no need to round
+				return Decimal{fixed: fixed}
+			}
+		} else if fixed, ok := mulRound(d.fixed, d2.fixed, pow10Table[precision-places]); ok {
+			return Decimal{fixed: fixed}
+		}
+	}
+	reportFallback("MulRound")
+	return optimizeFromDecimal(d.asFallback().Mul(d2.asFallback()).Round(places))
+}
+
+// optimized:
+// MulExact returns d * d2, or ErrOutOfRange if the result can not be
+// represented by the optimized fixed format.
+func (d Decimal) MulExact(d2 Decimal) (Decimal, error) {
+	return exact(d.Mul(d2))
+}
+
+// optimized:
+// MulPow10 returns d * 10^n, e.g. to convert dollars to cents, same as Shift(n).
+// A negative n divides, see DivPow10.
+func (d Decimal) MulPow10(n int32) Decimal {
+	if d.fallback == nil {
+		if n >= 0 && int64(n) < int64(len(pow10Table)) {
+			p := pow10Table[n]
+			if d.fixed >= minIntInFixed/p && d.fixed <= maxIntInFixed/p {
+				return Decimal{fixed: d.fixed * p}
+			}
+		} else if n < 0 && -int64(n) < int64(len(pow10Table)) {
+			if p := pow10Table[-n]; d.fixed%p == 0 {
+				return Decimal{fixed: d.fixed / p}
+			}
+		}
+	}
+	reportFallback("MulPow10")
+	return newFromDecimal(d.asFallback().Shift(n))
+}
+
+// optimized:
+// MulInt returns d * n
+func (d Decimal) MulInt(n int64) Decimal {
+	if d.fallback == nil {
+		if d.fixed == 0 || n == 0 {
+			return Zero
+		}
+
+		// check overflow
+		fixed := d.fixed * n
+		if fixed/n == d.fixed && fixed >= minIntInFixed && fixed <= maxIntInFixed {
+			return Decimal{fixed: fixed}
+		}
+	}
+	reportFallback("MulInt")
+	return newFromDecimal(d.asFallback().Mul(decimal.NewFromInt(n)))
+}
+
 // optimized:
 // Neg returns -d
 func (d Decimal) Neg() Decimal {
-	if d.fallback == nil {
+	// guard against malformed fixed (e.g. math.MinInt64), where negation overflows
+	if d.fallback == nil && d.fixed >= minIntInFixed {
 		return Decimal{fixed: -d.fixed}
 	}
-	return newFromDecimal(d.fallback.Neg())
+	reportFallback("Neg")
+	return d.negFallback()
+}
+
+// negFallback is split out of Neg to keep Neg small enough to be inlined.
+func (d Decimal) negFallback() Decimal {
+	return newFromDecimal(d.asFallback().Neg())
+}
+
+// optimized:
+// NextDown returns d - 10^-12, i.e. the next smaller value at the resolution of the fixed representation,
+// e.g. to test the boundary of a price limit.
+//
+// Fallback values are stepped by 10^-12 as well, even if they have more decimal places,
+// so the result is not necessarily the next smaller value of their own precision.
+func (d Decimal) NextDown() Decimal {
+	if d.fallback == nil && d.fixed > minIntInFixed {
+		return Decimal{fixed: d.fixed - 1}
+	}
+	return d.Sub(Decimal{fixed: 1})
+}
+
+// optimized:
+// NextUp returns d + 10^-12, i.e. the next larger value at the resolution of the fixed representation.
+// Like NextDown, fallback values are stepped by 10^-12 as well.
+func (d Decimal) NextUp() Decimal {
+	if d.fallback == nil && d.fixed < maxIntInFixed {
+		return Decimal{fixed: d.fixed + 1}
+	}
+	return d.Add(Decimal{fixed: 1})
 }
 
 // fallback:
@@ -629,12 +1788,109 @@ func (d Decimal) NumDigits() int {
 	return d.asFallback().NumDigits()
 }
 
+// optimized:
+// Parse parses b into d in place, same as NewFromString(string(b)), e.g. for bulk loaders
+// which own their buffers. b is not retained, and nothing is allocated unless d falls back.
+//
+// On error d is left unchanged.
+func (d *Decimal) Parse(b []byte) error {
+	if fixed, ok := parseFixed(b); ok {
+		d.fixed = fixed
+		d.fallback = nil
+		return nil
+	}
+
+	reportFallback("Parse")
+	fallback, err := decimal.NewFromString(string(b))
+	if err != nil {
+		return err
+	}
+	d.fixed = 0
+	d.fallback = &fallback
+	return nil
+}
+
 // fallback:
 // Pow returns d to the power d2
 func (d Decimal) Pow(d2 Decimal) Decimal {
 	return newFromDecimal(d.asFallback().Pow(d2.asFallback()))
 }
 
+// fallback:
+// PowSafe returns d to the power d2, or an error if there is no real result,
+// i.e. 0 to a negative power, or a negative d to a non-integer power, e.g. (-8)^(1/3).
+//
+// For integer d2 the result is the same as Pow. Unlike Pow, which only uses the integer part of
+// a non-integer d2, d^d2 = d^int(d2) * d^frac(d2), where d^frac(d2) is computed with math.Pow in float64, so the result is accurate to
+// about 15 significant digits only, e.g. 2^0.5 = 1.4142135623730951.
+func (d Decimal) PowSafe(d2 Decimal) (Decimal, error) {
+	if d.IsZero() && d2.IsNegative() {
+		return Zero, fmt.Errorf("can't raise 0 to negative power %s", d2)
+	}
+	if d2.IsInteger() {
+		return d.Pow(d2), nil
+	}
+	if d.IsNegative() {
+		return Zero, fmt.Errorf("can't raise negative %s to non-integer power %s", d, d2)
+	}
+	if d.IsZero() {
+		return Zero, nil
+	}
+
+	intPart := d2.Truncate(0)
+	fracPart, _ := d2.Sub(intPart).Float64()
+	f, _ := d.Float64()
+	p := math.Pow(f, fracPart)
+	if math.IsInf(p, 0) || p == 0 {
+		return Zero, fmt.Errorf("can't raise %s to power %s within float64 range", d, d2)
+	}
+
+	r := NewFromFloat(p)
+	if !intPart.IsZero() {
+		r = d.Pow(intPart).Mul(r)
+	}
+	return r, nil
+}
+
+// optimized:
+// Quantize rounds d with the given rounding mode to a multiple of 10^exp,
+// like quantize in the general decimal arithmetic spec, e.g. to match the scale of a DB column.
+//
+// The result of an optimized d with exp >= -12 stays optimized, and so reports Exponent() -12
+// while holding the quantized value, otherwise the result has exactly exponent exp.
+//
+// It returns an error if exp > 0 and rounding would lose integer digits, e.g. 1234 with exp 2.
+func (d Decimal) Quantize(exp int32, mode RoundingMode) (Decimal, error) {
+	r := d.RoundWithMode(-exp, mode)
+	if exp > 0 && !r.Equal(d) {
+		return Zero, fmt.Errorf("can't quantize %s to exponent %d without losing integer digits", d, exp)
+	}
+
+	if d.fallback == nil && exp >= -precision {
+		if exp > 0 {
+			// no rounding happened, keep d optimized
+			return d, nil
+		}
+		return r, nil
+	}
+
+	// e.g. RoundDown returns d as is if no rounding is needed, rescale to exactly exp,
+	// which is exact both ways since r is a multiple of 10^exp.
+	if r.fallback == nil {
+		// otherwise RoundWithMode already reported the fallback
+		reportFallback("Quantize")
+	}
+	rr := r.asFallback()
+	if e := rr.Exponent(); e > exp {
+		s := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(e-exp)), nil)
+		rr = decimal.NewFromBigInt(s.Mul(s, rr.Coefficient()), exp)
+	} else if e < exp {
+		s := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp-e)), nil)
+		rr = decimal.NewFromBigInt(s.Quo(rr.Coefficient(), s), exp)
+	}
+	return newFromDecimal(rr), nil
+}
+
 // fallback:
 // QuoRem does divsion with remainder
 func (d Decimal) QuoRem(d2 Decimal, precision int32) (Decimal, Decimal) {
@@ -648,52 +1904,49 @@ func (d Decimal) Rat() *big.Rat {
 	return d.asFallback().Rat()
 }
 
+// optimized:
+// Reciprocal returns 1 / d, same as NewFromInt(1).Div(d), e.g. to invert a conversion rate.
+// Like Div, it panics if d is zero.
+func (d Decimal) Reciprocal() Decimal {
+	return Decimal{fixed: scale}.Div(d)
+}
+
+// optimized:
+// ReciprocalExact returns 1 / d and whether it is exact, e.g. for 2, 4, 5 or 8,
+// otherwise, e.g. for 3 or 7, the result is rounded to DivisionPrecision same as Reciprocal.
+// It returns (Zero, false) if d is zero.
+func (d Decimal) ReciprocalExact() (Decimal, bool) {
+	if d.IsZero() {
+		return Zero, false
+	}
+	if d.fallback == nil {
+		if fixed, ok := div(scale, d.fixed); ok {
+			return Decimal{fixed: fixed}, true
+		}
+	}
+	// Div already reported the fallback, so check without Mul reporting it again
+	r := d.Reciprocal()
+	return r, r.asFallback().Mul(d.asFallback()).Equal(decimal.New(1, 0))
+}
+
 // optimized:
 // Round rounds the decimal to places decimal places.
 // If places < 0, it will round the integer part to the nearest 10^(-places).
 func (d Decimal) Round(places int32) Decimal {
-	if d.fallback == nil {
-		if places >= precision {
-			// no need to round
-			return d
-		}
-		if places >= 0 {
-			s := pow10Table[precision-places]
-			m := d.fixed % s
-			if m == 0 {
-				// no need to round
-				return d
-			}
+	return d.RoundWithMode(places, RoundHalfAwayFromZero)
+}
 
-			if m > 0 {
-				if m*2 >= s {
-					return Decimal{fixed: d.fixed - m + s}
-				} else {
-					return Decimal{fixed: d.fixed - m}
-				}
-			} else {
-				if -m*2 >= s {
-					return Decimal{fixed: d.fixed - m - s}
-				} else {
-					return Decimal{fixed: d.fixed - m}
-				}
-			}
-		}
-	}
-	return newFromDecimal(d.asFallback().Round(places))
-}
-
-// fallback:
+// optimized:
 // RoundBank rounds the decimal to places decimal places.
 // If the final digit to round is equidistant from the nearest two integers the
 // rounded value is taken as the even number
 //
 // If places < 0, it will round the integer part to the nearest 10^(-places).
 func (d Decimal) RoundBank(places int32) Decimal {
-	return newFromDecimal(d.asFallback().RoundBank(places))
+	return d.RoundWithMode(places, RoundHalfEven)
 }
 
-// fallback:
+// optimized:
 // RoundCash aka Cash/Penny/öre rounding rounds decimal to a specific
 // interval. The amount payable for a cash transaction is rounded to the nearest
 // multiple of the minimum currency unit available. The following intervals are
@@ -707,10 +1960,39 @@ func (d Decimal) RoundBank(places int32) Decimal {
 //
 // For more details: https://en.wikipedia.org/wiki/Cash_rounding
 func (d Decimal) RoundCash(interval uint8) Decimal {
-	return newFromDecimal(d.asFallback().RoundCash(interval))
+	if d.fallback == nil {
+		switch interval {
+		case 5, 10, 25, 50, 100:
+			// every interval divides scale, and so divides maxIntInFixed,
+			// which means rounding away from zero can not overflow.
+			s := int64(interval) * aCentInFixed
+			m := d.fixed % s
+			if m == 0 {
+				// no need to round
+				return d
+			}
+
+			if m > 0 {
+				if m*2 >= s {
+					return Decimal{fixed: d.fixed - m + s}
+				} else {
+					return Decimal{fixed: d.fixed - m}
+				}
+			} else {
+				if -m*2 >= s {
+					return Decimal{fixed: d.fixed - m - s}
+				} else {
+					return Decimal{fixed: d.fixed - m}
+				}
+			}
+		}
+		// unsupported interval, let fallback panic with the same message.
+	}
+	reportFallback("RoundCash")
+	return optimizeFromDecimal(d.asFallback().RoundCash(interval))
 }
 
-// fallback:
+// optimized:
 // RoundCeil rounds the decimal towards +infinity.
 //
 // Example:
@@ -720,10 +2002,10 @@ func (d Decimal) RoundCash(interval uint8) Decimal {
 //	NewFromFloat(1.1001).RoundCeil(2).String() // output: "1.11"
 //	NewFromFloat(-1.454).RoundCeil(1).String() // output: "-1.5"
 func (d Decimal) RoundCeil(places int32) Decimal {
-	return newFromDecimal(d.asFallback().RoundCeil(places))
+	return d.RoundWithMode(places, RoundCeil)
 }
 
-// fallback:
+// optimized:
 // RoundDown rounds the decimal towards zero.
 //
 // Example:
@@ -733,10 +2015,10 @@ func (d Decimal) RoundCeil(places int32) Decimal {
 //	NewFromFloat(1.1001).RoundDown(2).String() // output: "1.1"
 //	NewFromFloat(-1.454).RoundDown(1).String() // output: "-1.5"
 func (d Decimal) RoundDown(places int32) Decimal {
-	return newFromDecimal(d.asFallback().RoundDown(places))
+	return d.RoundWithMode(places, RoundDown)
 }
 
-// fallback:
+// optimized:
 // RoundFloor rounds the decimal towards -infinity.
 //
 // Example:
@@ -746,10 +2028,57 @@ func (d Decimal) RoundDown(places int32) Decimal {
 //	NewFromFloat(1.1001).RoundFloor(2).String() // output: "1.1"
 //	NewFromFloat(-1.454).RoundFloor(1).String() // output: "-1.4"
 func (d Decimal) RoundFloor(places int32) Decimal {
-	return newFromDecimal(d.asFallback().RoundFloor(places))
+	return d.RoundWithMode(places, RoundFloor)
 }
 
-// fallback:
+// optimized:
+// RoundIfApproxInteger returns d rounded to the nearest integer if IsApproxInteger(tolerance),
+// otherwise d as is, e.g. 2.9999999999 becomes 3 with tolerance 1e-9, but 2.99 stays 2.99.
+func (d Decimal) RoundIfApproxInteger(tolerance Decimal) Decimal {
+	if d.IsApproxInteger(tolerance) {
+		return d.Round(0)
+	}
+	return d
+}
+
+// optimized:
+// RoundToSignificant rounds the decimal to the given number of significant digits,
+// rounding half away from zero, same as Round.
+//
+// Example:
+//
+//	NewFromFloat(1234.5).RoundToSignificant(2).String()   // output: "1200"
+//	NewFromFloat(0.012345).RoundToSignificant(2).String() // output: "0.012"
+//	NewFromFloat(-9.96).RoundToSignificant(2).String()    // output: "-10"
+//
+// NOTE: this will panic if digits < 1.
+func (d Decimal) RoundToSignificant(digits int) Decimal {
+	if digits < 1 {
+		panic(fmt.Sprintf("invalid significant digits %d", digits))
+	}
+	if d.IsZero() {
+		return Zero
+	}
+
+	// the leading digit is at 10^(numDigits+exp-1)
+	var numDigits int
+	if d.fallback == nil {
+		u := d.fixed
+		if u < 0 {
+			u = -u
+		}
+		numDigits = 1
+		for numDigits < len(pow10Table) && u >= pow10Table[numDigits] {
+			numDigits++
+		}
+	} else {
+		numDigits = d.fallback.NumDigits()
+	}
+	places := int32(digits) - int32(numDigits) - d.Exponent()
+	return d.Round(places)
+}
+
+// optimized:
 // RoundUp rounds the decimal away from zero.
 //
 // Example:
@@ -759,18 +2088,113 @@ func (d Decimal) RoundFloor(places int32) Decimal {
 //	NewFromFloat(1.1001).RoundUp(2).String() // output: "1.11"
 //	NewFromFloat(-1.454).RoundUp(1).String() // output: "-1.4"
 func (d Decimal) RoundUp(places int32) Decimal {
-	return newFromDecimal(d.asFallback().RoundUp(places))
+	return d.RoundWithMode(places, RoundUp)
+}
+
+// optimized:
+// RoundWithMode rounds the decimal to places decimal places with the given rounding mode.
+// If places < 0, it will round the integer part to the nearest 10^(-places).
+//
+// NOTE: this will panic on unknown rounding mode.
+func (d Decimal) RoundWithMode(places int32, mode RoundingMode) Decimal {
+	if mode < RoundHalfAwayFromZero || mode > RoundUp {
+		panic(fmt.Sprintf("unknown rounding mode %d", mode))
+	}
+
+	if d.fallback == nil {
+		if places >= precision {
+			// no need to round
+			return d
+		}
+		if places >= 0 {
+			// pow10Table[precision-places] divides scale, and so divides maxIntInFixed,
+			// which means rounding away from zero can not overflow.
+			return Decimal{fixed: roundFixed(d.fixed, pow10Table[precision-places], mode)}
+		}
+		if int(precision-places) < len(pow10Table) {
+			// negative places round integer digits, e.g. 1234 => 1200 with places -2,
+			// where rounding away from zero can leave the optimized range.
+			s := pow10Table[precision-places]
+			if d.fixed <= math.MaxInt64-s && d.fixed >= -(math.MaxInt64-s) {
+				if fixed := roundFixed(d.fixed, s, mode); fixed >= minIntInFixed && fixed <= maxIntInFixed {
+					return Decimal{fixed: fixed}
+				}
+			}
+		}
+	}
+
+	// rounding is often the last step before storage,
+	// so re-optimize results which fit, e.g. a fallback product rounded to cents.
+	reportFallback("RoundWithMode")
+	dd := d.asFallback()
+	switch mode {
+	case RoundHalfEven:
+		dd = dd.RoundBank(places)
+	case RoundHalfUp:
+		// round half towards +infinity is floor(d + 0.5 unit)
+		dd = dd.Add(decimal.New(5, -places-1)).RoundFloor(places)
+	case RoundCeil:
+		dd = dd.RoundCeil(places)
+	case RoundFloor:
+		dd = dd.RoundFloor(places)
+	case RoundDown:
+		dd = dd.RoundDown(places)
+	case RoundUp:
+		dd = dd.RoundUp(places)
+	default:
+		dd = dd.Round(places)
+	}
+	return optimizeFromDecimal(dd)
+}
+
+// optimized:
+// ScaleTo returns d at exponent exp if d is exactly representable there,
+// otherwise an error, i.e. it never rounds, unlike Quantize.
+//
+// Same as Quantize, the result of an optimized d with exp >= -12 stays optimized.
+func (d Decimal) ScaleTo(exp int32) (Decimal, error) {
+	if d.fallback == nil && exp >= -precision && int(exp)+precision < len(pow10Table) {
+		if d.fixed%pow10Table[int(exp)+precision] != 0 {
+			return Zero, fmt.Errorf("can't scale %s to exponent %d without rounding", d, exp)
+		}
+		return d, nil
+	}
+
+	r, err := d.Quantize(exp, RoundDown)
+	if err != nil || !r.Equal(d) {
+		return Zero, fmt.Errorf("can't scale %s to exponent %d without rounding", d, exp)
+	}
+	return r, nil
 }
 
 // optimized:
 // sql.Scanner interface
+//
+// NULL (nil) can't be scanned into a Decimal, which is not nullable, use NullDecimal instead.
+// bool is rejected as well rather than mapped to 0 / 1, as it is most likely a mis-mapped column.
+// d is left unchanged on error.
+//
+// Leading and trailing ASCII whitespace of string values is ignored, e.g. "  1.23 " from padded CHAR columns.
+// An empty (or blank) string is rejected like NULL.
 func (d *Decimal) Scan(value interface{}) error {
 	switch v := value.(type) {
+	case nil:
+		return errors.New("can't scan NULL into Decimal, use NullDecimal instead")
+
+	case bool:
+		return fmt.Errorf("can't scan bool %t into Decimal", v)
+
 	case float32:
+		if StrictFloatScan {
+			return d.scanFloatExact(float64(v))
+		}
 		*d = NewFromFloat32(v)
 		return nil
 
 	case float64:
+		if StrictFloatScan {
+			return d.scanFloatExact(v)
+		}
 		*d = NewFromFloat(v)
 		return nil
 
@@ -779,30 +2203,62 @@ func (d *Decimal) Scan(value interface{}) error {
 		return nil
 
 	case []byte:
-		fixed, ok := parseFixed(v)
+		v = trimSpace(v)
+		if len(v) == 0 {
+			return errors.New("can't scan empty string into Decimal, use NullDecimal instead")
+		}
+		fixed, ok := parseFixed(unquoteIfQuoted(v))
 		if ok {
 			d.fixed = fixed
 			d.fallback = nil
 			return nil
 		}
+		value = v
 
 	case string:
-		fixed, ok := parseFixed(v)
+		v = trimSpace(v)
+		if len(v) == 0 {
+			return errors.New("can't scan empty string into Decimal, use NullDecimal instead")
+		}
+		fixed, ok := parseFixed(unquoteIfQuoted(v))
 		if ok {
 			d.fixed = fixed
 			d.fallback = nil
 			return nil
 		}
+		value = v
+
+	case sql.RawBytes:
+		// the driver reuses the underlying buffer after Scan returns,
+		// this is safe as the bytes are parsed synchronously and never retained.
+		return d.Scan([]byte(v))
+
+	case json.Number:
+		// e.g. values of map[string]interface{} decoded with json.Decoder.UseNumber
+		return d.Scan(string(v))
 	}
 
+	reportFallback("Scan")
 	var fallback decimal.Decimal
 	if err := fallback.Scan(value); err != nil {
 		return err
 	}
+	// reset fixed as well, d may be reused, e.g. scanned row by row.
+	d.fixed = 0
 	d.fallback = &fallback
 	return nil
 }
 
+// scanFloatExact is Scan of a float under StrictFloatScan.
+func (d *Decimal) scanFloatExact(f float64) error {
+	v, err := NewFromFloatExact(f)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
 // fallback:
 // Binary shift left (k > 0) or right (k < 0).
 func (d Decimal) Shift(shift int32) Decimal {
@@ -825,7 +2281,7 @@ func (d Decimal) Sign() int {
 		}
 		return 0
 	}
-	return d.asFallback().Sign()
+	return d.fallback.Sign()
 }
 
 // fallback:
@@ -834,6 +2290,65 @@ func (d Decimal) Sin() Decimal {
 	return newFromDecimal(d.asFallback().Sin())
 }
 
+// optimized:
+// SplitN splits d into n parts with places decimal places, which sum exactly to d,
+// e.g. to distribute cash evenly. The remainder units go to the first parts:
+//
+//	NewFromFloat(0.1).SplitN(3, 2)  // output: [0.04 0.03 0.03]
+//	NewFromFloat(-0.1).SplitN(3, 2) // output: [-0.04 -0.03 -0.03]
+//
+// It returns an error if n <= 0, or d has more than places decimal places.
+func (d Decimal) SplitN(n int, places int32) ([]Decimal, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("can't split %s into %d parts", d, n)
+	}
+
+	if d.fallback == nil && places >= 0 && places <= precision {
+		unit := pow10Table[precision-places]
+		if d.fixed%unit != 0 {
+			return nil, fmt.Errorf("can't split %s into parts with %d decimal places", d, places)
+		}
+
+		units := d.fixed / unit
+		q, r := units/int64(n), units%int64(n)
+		one := int64(1)
+		if r < 0 {
+			one, r = -1, -r
+		}
+
+		parts := make([]Decimal, n)
+		for i := range parts {
+			part := q
+			if int64(i) < r {
+				part += one
+			}
+			parts[i] = Decimal{fixed: part * unit}
+		}
+		return parts, nil
+	}
+
+	reportFallback("SplitN")
+	units := d.asFallback().Shift(places)
+	if !units.IsInteger() {
+		return nil, fmt.Errorf("can't split %s into parts with %d decimal places", d, places)
+	}
+
+	// |r| < n, so it fits in int64
+	q, r := new(big.Int).QuoRem(units.BigInt(), big.NewInt(int64(n)), new(big.Int))
+	one := big.NewInt(int64(r.Sign()))
+	rest := r.Abs(r).Int64()
+
+	parts := make([]Decimal, n)
+	for i := range parts {
+		part := q
+		if int64(i) < rest {
+			part = new(big.Int).Add(q, one)
+		}
+		parts[i] = optimizeFromDecimal(decimal.NewFromBigInt(part, -places))
+	}
+	return parts, nil
+}
+
 // optimized:
 // String returns the string representation of the decimal
 // with the fixed point.
@@ -841,70 +2356,110 @@ func (d Decimal) String() string {
 	if d.fallback == nil {
 		// cache hit
 		if d.fixed <= a1000InFixed && d.fixed >= aNeg1000InFixed && d.fixed%aCentInFixed == 0 {
+			cacheOnce.Do(initCache)
 			return stringCache[d.fixed/aCentInFixed+cacheOffset]
 		}
 
-		// "-9223372.000000000000" => max length = 21 bytes
-		var s [21]byte
-		start := 7
-		end := 8
-
-		var ufixed uint64
-		if d.fixed >= 0 {
-			ufixed = uint64(d.fixed)
-		} else {
-			ufixed = uint64(d.fixed * -1)
+		// whole number, e.g. quantities, no fractional digits to format
+		if d.fixed%scale == 0 {
+			i := d.fixed / scale
+			if c, _ := intCache.Load().(*intStringCache); c != nil && i >= -c.max && i <= c.max {
+				return c.strings[i+c.max]
+			}
+			return strconv.FormatInt(i, 10)
 		}
 
-		integerPart := ufixed / scale
-		fractionalPart := ufixed % scale
+		return fixedString(d.fixed, 0)
+	}
 
-		// integer part
-		if integerPart == 0 {
-			s[start] = '0'
-		} else {
-			for integerPart >= 10 {
-				s[start] = byte(integerPart%10 + '0')
-				start--
-				integerPart /= 10
-			}
-			s[start] = byte(integerPart + '0')
-		}
-
-		// fractional part
-		if fractionalPart > 0 {
-			s[8] = '.'
-			for i := 20; i > 8; i-- {
-				is := fractionalPart % 10
-				fractionalPart /= 10
-				if is != 0 {
-					s[i] = byte(is + '0')
-					end = i + 1
-					for j := i - 1; j > 8; j-- {
-						s[j] = byte(fractionalPart%10 + '0')
-						fractionalPart /= 10
-					}
-					break
-				}
-			}
-		}
+	return d.fallback.String()
+}
 
-		// sign part
-		if d.fixed < 0 {
-			start -= 1
-			s[start] = '-'
+// places returns the number of significant decimal places, i.e. without trailing zeros.
+func (d Decimal) places() int32 {
+	if d.fallback == nil {
+		f := d.fixed % scale
+		if f == 0 {
+			return 0
+		}
+		n := int32(precision)
+		for f%10 == 0 {
+			f /= 10
+			n--
 		}
+		return n
+	}
 
-		return string(s[start:end])
+	s := d.fallback.String()
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return int32(len(s) - i - 1)
 	}
+	return 0
+}
 
-	return d.fallback.String()
+// optimized:
+// StringWithMinPlaces returns the string representation of the decimal
+// with at least min digits after the decimal point. Unlike StringFixed,
+// it never rounds away significant digits.
+//
+// Example:
+//
+//	NewFromFloat(1.5).StringWithMinPlaces(2)   // output: "1.50"
+//	NewFromFloat(1.555).StringWithMinPlaces(2) // output: "1.555"
+//	NewFromFloat(1).StringWithMinPlaces(0)     // output: "1"
+func (d Decimal) StringWithMinPlaces(min int32) string {
+	if min < 0 {
+		min = 0
+	}
+	if d.fallback == nil && min <= precision {
+		return fixedString(d.fixed, int(min))
+	}
+
+	s := d.String()
+	places := int32(0)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		places = int32(len(s) - i - 1)
+	} else if min > 0 {
+		s += "."
+	}
+	if places < min {
+		s += strings.Repeat("0", int(min-places))
+	}
+	return s
 }
 
-// fallback:
+// optimized:
+// StringExponent returns the raw coefficient and exponent of the decimal, for debugging precision issues.
+//
+//	NewFromInt(123).StringExponent()              // output: "123000000000000e-12"
+//	RequireFromString("1.5e20").StringExponent() // output: "15e19"
+func (d Decimal) StringExponent() string {
+	if d.fallback == nil {
+		b := make([]byte, 0, 24)
+		b = strconv.AppendInt(b, d.fixed, 10)
+		b = append(b, 'e')
+		b = strconv.AppendInt(b, -precision, 10)
+		return string(b)
+	}
+	return d.fallback.Coefficient().String() + "e" + strconv.FormatInt(int64(d.fallback.Exponent()), 10)
+}
+
+// optimized:
 // StringFixed returns a rounded fixed-point string with places digits after
 // the decimal point.
+//
+// If places < 0, it will round the integer part to the nearest 10^(-places).
+//
+// Example:
+//
+//	NewFromFloat(5.45).StringFixed(1)  // output: "5.5"
+//	NewFromFloat(5).StringFixed(2)     // output: "5.00"
+//	NewFromFloat(1234).StringFixed(-2) // output: "1200"
 func (d Decimal) StringFixed(places int32) string {
+	var buf [24]byte
+	if b, ok := d.appendStringFixed(buf[:0], places); ok {
+		return string(b)
+	}
 	return d.asFallback().StringFixed(places)
 }
 
@@ -922,6 +2477,31 @@ func (d Decimal) StringFixedCash(interval uint8) string {
 	return d.asFallback().StringFixedCash(interval)
 }
 
+// optimized:
+// StringNoLeadingZero returns the same as String, but without the zero integer part
+// for |d| < 1, e.g. for fixed-width legacy file formats. Zero is still "0".
+//
+//	NewFromFloat(0.5).StringNoLeadingZero()  // output: ".5"
+//	NewFromFloat(-0.5).StringNoLeadingZero() // output: "-.5"
+//	NewFromFloat(1.5).StringNoLeadingZero()  // output: "1.5"
+func (d Decimal) StringNoLeadingZero() string {
+	var buf [24]byte
+	var b []byte
+	if d.fallback == nil {
+		b = appendFixedString(buf[:0], d.fixed, 0)
+	} else {
+		b = append(buf[:0], d.fallback.String()...)
+	}
+
+	if len(b) > 2 && b[0] == '0' && b[1] == '.' {
+		b = b[1:]
+	} else if len(b) > 3 && b[0] == '-' && b[1] == '0' && b[2] == '.' {
+		b[1] = '-'
+		b = b[1:]
+	}
+	return string(b)
+}
+
 // fallback:
 // DEPRECATED! Use StringFixed instead.
 func (d Decimal) StringScaled(exp int32) string {
@@ -929,9 +2509,53 @@ func (d Decimal) StringScaled(exp int32) string {
 }
 
 // optimized:
-// Sub returns d - d2.
+// Sub returns d - d2, optimized if it is representable, same as Add.
 func (d Decimal) Sub(d2 Decimal) Decimal {
-	return d.Add(d2.Neg())
+	// same as Add, but checks overflow of d - d2 directly instead of going through d + (-d2)
+	if d.fallback == nil && d2.fallback == nil {
+		if d2.fixed < 0 {
+			if d.fixed <= maxIntInFixed+d2.fixed {
+				return Decimal{fixed: d.fixed - d2.fixed}
+			}
+		} else {
+			if d.fixed >= minIntInFixed+d2.fixed {
+				return Decimal{fixed: d.fixed - d2.fixed}
+			}
+		}
+	}
+	return d.subFallback(d2)
+}
+
+func (d Decimal) subFallback(d2 Decimal) Decimal {
+	reportFallback("Sub")
+	return sumFromDecimal(d.asFallback().Sub(d2.asFallback()), d, d2)
+}
+
+// sumFromDecimal returns the fallback result r of d + d2 or d - d2, optimized if it is representable,
+// e.g. 1e-13 - 1e-13. Otherwise, if d or d2 is optimized, the trailing zeros introduced by
+// its exponent -12 are removed, e.g. 1 + 1e20 is 1e20 + 1 rather than (1e32 + 1e12) * 10^-12.
+func sumFromDecimal(r decimal.Decimal, d, d2 Decimal) Decimal {
+	if fixed, ok := fixedFromDecimal(r); ok {
+		return Decimal{fixed: fixed}
+	}
+	return newFromDecimal(canonicalSum(r, d.fallback == nil || d2.fallback == nil))
+}
+
+// canonicalSum removes the trailing zeros of the fallback sum r introduced by an optimized operand,
+// see sumFromDecimal, shared with Builder.
+func canonicalSum(r decimal.Decimal, hasOptimized bool) decimal.Decimal {
+	if !hasOptimized {
+		return r
+	}
+	c, exp := trimTrailingZeros(r.Coefficient(), r.Exponent())
+	return decimal.NewFromBigInt(c, exp)
+}
+
+// optimized:
+// SubExact returns d - d2, or ErrOutOfRange if the result can not be
+// represented by the optimized fixed format.
+func (d Decimal) SubExact(d2 Decimal) (Decimal, error) {
+	return exact(d.Sub(d2))
 }
 
 // fallback:
@@ -944,16 +2568,40 @@ func (d Decimal) Tan() Decimal {
 // Truncate truncates off digits from the number, without rounding.
 func (d Decimal) Truncate(precision int32) Decimal {
 	if d.fallback == nil {
-		s := pow10Table[12-precision]
-		return Decimal{fixed: d.fixed / s * s}
+		if precision < 0 {
+			// same as decimal.Decimal, negative precision is a no-op
+			return d
+		}
+		return d.RoundWithMode(precision, RoundDown)
 	}
-	return newFromDecimal(d.asFallback().Truncate(precision))
+	reportFallback("Truncate")
+	return optimizeFromDecimal(d.asFallback().Truncate(precision))
 }
 
-// fallback:
-// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. As a string representation
-// is already used when encoding to text, this method stores that string as []byte
+// optimized:
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It accepts both the decimal.Decimal binary format and the compact format of MarshalBinaryCompact,
+// which are told apart by the leading byte.
 func (d *Decimal) UnmarshalBinary(data []byte) error {
+	if len(data) > 0 {
+		switch data[0] {
+		case binaryVersionFixed:
+			if len(data) != 9 {
+				return fmt.Errorf("can't unmarshal binary of %d bytes into Decimal", len(data))
+			}
+			fixed := int64(binary.BigEndian.Uint64(data[1:]))
+			if fixed < minIntInFixed || fixed > maxIntInFixed {
+				return fmt.Errorf("can't unmarshal fixed %d into Decimal: %w", fixed, ErrOutOfRange)
+			}
+			d.fixed = fixed
+			d.fallback = nil
+			return nil
+		case binaryVersionFallback:
+			data = data[1:]
+		}
+	}
+
+	reportFallback("UnmarshalBinary")
 	var dd decimal.Decimal
 	if err := dd.UnmarshalBinary(data); err != nil {
 		return err
@@ -966,17 +2614,26 @@ func (d *Decimal) UnmarshalBinary(data []byte) error {
 
 // optimized:
 // UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// A bare `null` is decoded as Zero, use NullDecimal if null has to be told apart from zero.
 func (d *Decimal) UnmarshalJSON(decimalBytes []byte) error {
-	if fixed, ok := parseFixed(decimalBytes); ok {
+	if string(decimalBytes) == "null" {
+		*d = Zero
+		return nil
+	}
+
+	if fixed, ok := parseFixed(unquoteIfQuoted(decimalBytes)); ok {
 		d.fixed = fixed
 		d.fallback = nil
 		return nil
 	}
 
+	reportFallback("UnmarshalJSON")
 	var fallback decimal.Decimal
 	if err := fallback.UnmarshalJSON(decimalBytes); err != nil {
 		return err
 	}
+	d.fixed = 0
 	d.fallback = &fallback
 	return nil
 }
@@ -991,6 +2648,7 @@ func (d *Decimal) UnmarshalText(text []byte) error {
 		return nil
 	}
 
+	reportFallback("UnmarshalText")
 	var dd decimal.Decimal
 	if err := dd.UnmarshalText(text); err != nil {
 		return err
@@ -1003,13 +2661,12 @@ func (d *Decimal) UnmarshalText(text []byte) error {
 
 // optimized:
 // sql.Valuer interface
+//
+// Whole numbers skip the fractional formatting, same as String, e.g. share counts in bulk inserts,
+// use EnableIntCache to cache them beyond the default cache of -1000.00 to 1000.00.
 func (d Decimal) Value() (driver.Value, error) {
 	if d.fallback == nil {
-		// cache hit
-		if d.fixed <= a1000InFixed && d.fixed >= aNeg1000InFixed && d.fixed%aCentInFixed == 0 {
-			return valueCache[d.fixed/aCentInFixed+cacheOffset], nil
-		}
-
+		// String hits stringCache where possible
 		return d.String(), nil
 	}
 
@@ -1030,42 +2687,527 @@ func (d Decimal) IsOptimized() bool {
 	return d.fallback == nil
 }
 
-// NullDecimal support
-type NullDecimal struct {
-	Decimal Decimal
-	Valid   bool
+// EncodeFixed returns the fixed representation of d (value * 10^12)
+// and whether d can be represented in fixed format exactly.
+// A fallback value is encoded as well if it's within the optimized range with up to 12 precision.
+// When false is returned, the value should be stored out-of-band, e.g. via MarshalBinary.
+func (d Decimal) EncodeFixed() (int64, bool) {
+	if d.fallback == nil {
+		return d.fixed, true
+	}
+	return fixedFromDecimal(*d.fallback)
 }
 
-func NewNullDecimal(d Decimal) NullDecimal {
-	return NullDecimal{
-		Decimal: d,
-		Valid:   true,
-	}
+// DecodeFixed returns an optimized Decimal from the fixed representation returned by EncodeFixed.
+//
+// NOTE: this bypasses validation and assumes trusted input,
+// i.e. v must be within the optimized range (see EncodeFixed).
+func DecodeFixed(v int64) Decimal {
+	return Decimal{fixed: v}
 }
 
-func (d NullDecimal) MarshalJSON() ([]byte, error) {
-	if !d.Valid {
-		return []byte("null"), nil
+// EncodeColumn encodes ds as integers scaled by 10^places, e.g. for arrow / parquet
+// decimal columns where places is the scale of the column.
+//
+// valid[i] reports whether ds[i] is encoded exactly, i.e. it has no more than places
+// digits after the decimal point and the scaled value fits in int64. Values that are
+// not valid are encoded as 0 and need to be handled separately by the caller.
+//
+// It returns an error if places is not within [0, 12].
+func EncodeColumn(ds []Decimal, places int32) ([]int64, []bool, error) {
+	if places < 0 || places > precision {
+		return nil, nil, fmt.Errorf("can't encode column with scale %d, scale should be within [0, %d]", places, precision)
 	}
-	return d.Decimal.MarshalJSON()
-}
 
-func (d NullDecimal) MarshalText() (text []byte, err error) {
-	if !d.Valid {
-		return []byte{}, nil
+	s := pow10Table[precision-places]
+
+	values := make([]int64, len(ds))
+	valid := make([]bool, len(ds))
+	for i, d := range ds {
+		if d.fallback == nil {
+			if d.fixed%s == 0 {
+				values[i] = d.fixed / s
+				valid[i] = true
+			}
+			continue
+		}
+
+		// fallback
+		shifted := d.fallback.Shift(places)
+		if !shifted.IsInteger() {
+			continue
+		}
+		v := shifted.BigInt()
+		if v.IsInt64() {
+			values[i] = v.Int64()
+			valid[i] = true
+		}
 	}
-	return d.Decimal.MarshalText()
+	return values, valid, nil
 }
 
-func (d *NullDecimal) Scan(value interface{}) error {
-	if value == nil {
-		d.Valid = false
-		return nil
-	}
+// Validation support
+
+// ValidationOption is a rule checked by Decimal.Validate,
+// e.g. NonNegative(), MaxPlaces(n) and InRange(min, max).
+type ValidationOption func(d Decimal) error
+
+// Validate checks d against opts in order and returns the first violation, if any.
+// This is useful to enforce domain rules after unmarshaling, e.g.
+//
+//	err := d.Validate(NonNegative(), MaxPlaces(2))
+func (d Decimal) Validate(opts ...ValidationOption) error {
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NonNegative requires the decimal to be >= 0.
+func NonNegative() ValidationOption {
+	return func(d Decimal) error {
+		if d.IsNegative() {
+			return fmt.Errorf("decimal %s is negative", d)
+		}
+		return nil
+	}
+}
+
+// MaxPlaces requires the decimal to have at most n significant digits after the decimal point,
+// e.g. 1.50 passes MaxPlaces(1) as trailing zeros are not significant.
+func MaxPlaces(n int32) ValidationOption {
+	return func(d Decimal) error {
+		if d.fallback == nil && n >= 0 {
+			if n >= precision || d.fixed%pow10Table[precision-n] == 0 {
+				return nil
+			}
+		} else if d.Equal(d.RoundDown(n)) {
+			return nil
+		}
+		return fmt.Errorf("decimal %s has more than %d decimal places", d, n)
+	}
+}
+
+// InRange requires the decimal to be within [min, max].
+func InRange(min, max Decimal) ValidationOption {
+	return func(d Decimal) error {
+		if d.LessThan(min) || d.GreaterThan(max) {
+			return fmt.Errorf("decimal %s is out of range [%s, %s]", d, min, max)
+		}
+		return nil
+	}
+}
+
+// Builder support
+
+// Builder performs a chain of operations and materializes the result with Decimal(),
+// same as chaining Decimal methods, e.g.
+//
+//	b := NewBuilder(x)
+//	result := b.Mul(y).Add(z).Div(w).Decimal()
+//
+// is the same as x.Mul(y).Add(z).Div(w).
+//
+// Each fallback Decimal keeps its decimal.Decimal behind a pointer, so every operation on
+// fallback values allocates it on top of the allocations of decimal.Decimal itself.
+// Builder keeps the intermediate decimal.Decimal by value instead, and only allocates
+// the pointer once when the result is materialized. The saving is modest, as decimal.Decimal
+// still allocates a new big.Int for every operation, which can't be pooled since
+// decimal.Decimal values are immutable and may share it.
+//
+// A Builder is not safe for concurrent use.
+type Builder struct {
+	d          Decimal
+	fallback   decimal.Decimal
+	isFallback bool
+}
+
+// NewBuilder returns a Builder starting with d.
+func NewBuilder(d Decimal) Builder {
+	var b Builder
+	b.set(d)
+	return b
+}
+
+// Add sets the current value to current + d.
+func (b *Builder) Add(d Decimal) *Builder {
+	if b.isFallback {
+		reportFallback("Add")
+		b.setSum(b.fallback.Add(d.asFallback()), d)
+	} else {
+		b.set(b.d.Add(d))
+	}
+	return b
+}
+
+// Sub sets the current value to current - d.
+func (b *Builder) Sub(d Decimal) *Builder {
+	if b.isFallback {
+		reportFallback("Sub")
+		b.setSum(b.fallback.Sub(d.asFallback()), d)
+	} else {
+		b.set(b.d.Sub(d))
+	}
+	return b
+}
+
+// Mul sets the current value to current * d.
+// Same as Decimal.Mul, a fallback product stays fallback.
+func (b *Builder) Mul(d Decimal) *Builder {
+	if b.isFallback {
+		reportFallback("Mul")
+		b.fallback = b.fallback.Mul(d.asFallback())
+	} else {
+		b.set(b.d.Mul(d))
+	}
+	return b
+}
+
+// Div sets the current value to current / d, same as Decimal.Div, where a fallback quotient stays fallback.
+func (b *Builder) Div(d Decimal) *Builder {
+	if b.isFallback {
+		reportFallback("Div")
+		b.fallback = b.fallback.DivRound(d.asFallback(), int32(DivisionPrecision))
+	} else {
+		b.set(b.d.Div(d))
+	}
+	return b
+}
+
+// Decimal returns the current value.
+func (b *Builder) Decimal() Decimal {
+	if b.isFallback {
+		return newFromDecimal(b.fallback)
+	}
+	return b.d
+}
+
+// setSum sets the fallback result r of current + d or current - d, same as sumFromDecimal,
+// i.e. it switches back to the optimized value if r is representable.
+func (b *Builder) setSum(r decimal.Decimal, d Decimal) {
+	if fixed, ok := fixedFromDecimal(r); ok {
+		b.d = Decimal{fixed: fixed}
+		b.isFallback = false
+		return
+	}
+	// the current value is fallback, so only d can be optimized
+	b.fallback = canonicalSum(r, d.fallback == nil)
+}
+
+func (b *Builder) set(d Decimal) {
+	if d.fallback != nil {
+		b.fallback = *d.fallback
+		b.isFallback = true
+	} else {
+		b.d = d
+	}
+}
+
+// Decoder support
+
+// Decoder reads whitespace separated decimals from a stream,
+// e.g. one decimal per line, where quoted values like "1.5" are accepted as well.
+//
+// The internal buffer is reused, so decoding optimized values doesn't allocate.
+type Decoder struct {
+	scanner *bufio.Scanner
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	return &Decoder{scanner: scanner}
+}
+
+// Decode returns the next decimal, or io.EOF when there is no more input.
+func (dec *Decoder) Decode() (Decimal, error) {
+	if !dec.scanner.Scan() {
+		if err := dec.scanner.Err(); err != nil {
+			return Zero, err
+		}
+		return Zero, io.EOF
+	}
+
+	v := unquoteIfQuoted(dec.scanner.Bytes())
+	if fixed, ok := parseFixed(v); ok {
+		return Decimal{fixed: fixed}, nil
+	}
+
+	// fallback
+	reportFallback("Decode")
+	d, err := decimal.NewFromString(string(v))
+	if err != nil {
+		return Zero, err
+	}
+	return newFromDecimal(d), nil
+}
+
+// FixedJSON support
+
+// FixedJSON marshals Decimal to JSON with exactly Places decimal places, e.g. "1.50" with Places 2,
+// for API contracts that require a stable number of decimal places.
+//
+//	type Order struct {
+//		Price alpacadecimal.FixedJSON `json:"price"`
+//	}
+//
+//	order := Order{Price: alpacadecimal.FixedJSON{Decimal: price, Places: 2}}
+//
+// Values are rounded half away from zero, same as StringFixed, and quoted unless MarshalJSONWithoutQuotes is set.
+// Places is not part of the JSON, unmarshaling accepts any precision and stores it as is.
+type FixedJSON struct {
+	Decimal
+	Places int32
+}
+
+// optimized:
+// MarshalJSON implements the json.Marshaler interface.
+func (d FixedJSON) MarshalJSON() ([]byte, error) {
+	return d.appendJSON(make([]byte, 0, 32))
+}
+
+// appendJSON appends the JSON encoding of d to b, shared by json v1 and v2 marshaling.
+func (d FixedJSON) appendJSON(b []byte) ([]byte, error) {
+	if !MarshalJSONWithoutQuotes {
+		b = append(b, '"')
+	}
+
+	start := len(b)
+	if v, ok := d.Decimal.appendStringFixed(b, d.Places); ok {
+		b = v
+	} else {
+		b = append(b, d.Decimal.StringFixed(d.Places)...)
+	}
+	if !isPlainNumber(b[start:]) {
+		return nil, fmt.Errorf("can't marshal decimal %q to json", b[start:])
+	}
+
+	if !MarshalJSONWithoutQuotes {
+		b = append(b, '"')
+	}
+	return b, nil
+}
+
+// Scanner support
+
+// Scanner scans a column into Decimal with a per-column policy, e.g.
+//
+//	var price alpacadecimal.Decimal
+//	err := row.Scan(alpacadecimal.Scanner{Decimal: &price, Strict: true})
+//
+// In strict mode, values which can't be represented exactly by the optimized
+// fixed format are rejected with ErrOutOfRange instead of falling back to decimal.Decimal,
+// including float sources which can't be converted with 12 precision, same as StrictFloatScan.
+//
+// DecimalSeparator and GroupSeparator are for text columns of localized exports, e.g.
+// "1.234,5" with DecimalSeparator ',' and GroupSeparator '.'. The zero values mean '.' and
+// no grouping. With a DecimalSeparator other than '.', a '.' which is not the GroupSeparator
+// is rejected, as it is ambiguous.
+type Scanner struct {
+	Decimal          *Decimal
+	Strict           bool
+	DecimalSeparator byte
+	GroupSeparator   byte
+}
+
+// Scan implements the sql.Scanner interface.
+// Decimal is left unchanged on error.
+func (s Scanner) Scan(value interface{}) error {
+	// same as StrictFloatScan, float32 is converted exactly,
+	// e.g. 0.1 is 0.100000001490116119384765625, rather than its shortest representation.
+	if v, ok := value.(float32); ok && s.Strict {
+		value = float64(v)
+	}
+
+	if (s.DecimalSeparator != 0 && s.DecimalSeparator != '.') || s.GroupSeparator != 0 {
+		var err error
+		if value, err = s.normalize(value); err != nil {
+			return err
+		}
+	}
+
+	var d Decimal
+	if err := d.Scan(value); err != nil {
+		return err
+	}
+
+	if s.Strict {
+		x, err := exact(d)
+		if err != nil {
+			return fmt.Errorf("can't scan %s into Decimal: %w", d, err)
+		}
+		d = x
+	}
+	*s.Decimal = d
+	return nil
+}
+
+// normalize rewrites text values with the separators of s to the '.' decimal point without grouping.
+func (s Scanner) normalize(value interface{}) (interface{}, error) {
+	var v []byte
+	switch x := value.(type) {
+	case string:
+		v = []byte(x)
+	case []byte:
+		v = x
+	case sql.RawBytes:
+		v = x
+	default:
+		return value, nil
+	}
+
+	sep := s.DecimalSeparator
+	if sep == 0 {
+		sep = '.'
+	}
+	if sep == s.GroupSeparator {
+		return nil, fmt.Errorf("can't scan with the same decimal and group separator %q", sep)
+	}
+
+	b := make([]byte, 0, len(v))
+	for _, c := range v {
+		switch {
+		case s.GroupSeparator != 0 && c == s.GroupSeparator:
+		case c == sep:
+			b = append(b, '.')
+		case c == '.':
+			return nil, fmt.Errorf("can't scan %q into Decimal with decimal separator %q", v, sep)
+		default:
+			b = append(b, c)
+		}
+	}
+	return b, nil
+}
+
+// NullDecimal support
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool
+}
+
+func NewNullDecimal(d Decimal) NullDecimal {
+	return NullDecimal{
+		Decimal: d,
+		Valid:   true,
+	}
+}
+
+// NullDecimalFromPtr returns an invalid NullDecimal for nil, or a valid one holding *d otherwise.
+func NullDecimalFromPtr(d *Decimal) NullDecimal {
+	if d == nil {
+		return NullDecimal{}
+	}
+	return NewNullDecimal(*d)
+}
+
+// NewNullDecimalFromDecimal converts a decimal.NullDecimal to NullDecimal.
+func NewNullDecimalFromDecimal(d decimal.NullDecimal) NullDecimal {
+	if !d.Valid {
+		return NullDecimal{}
+	}
+	return NewNullDecimal(NewFromDecimal(d.Decimal))
+}
+
+// Add returns d + d2, or an invalid NullDecimal if either operand is invalid.
+//
+// NOTE: this follows SQL NULL propagation, i.e. null is not treated as zero.
+func (d NullDecimal) Add(d2 NullDecimal) NullDecimal {
+	if !d.Valid || !d2.Valid {
+		return NullDecimal{}
+	}
+	return NewNullDecimal(d.Decimal.Add(d2.Decimal))
+}
+
+// AppendText implements the encoding.TextAppender interface,
+// it appends the same as MarshalText to b, i.e. nothing for an invalid NullDecimal.
+func (d NullDecimal) AppendText(b []byte) ([]byte, error) {
+	if !d.Valid {
+		return b, nil
+	}
+	return d.Decimal.AppendText(b)
+}
+
+func (d NullDecimal) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return d.Decimal.MarshalJSON()
+}
+
+func (d NullDecimal) MarshalText() (text []byte, err error) {
+	if !d.Valid {
+		return []byte{}, nil
+	}
+	return d.Decimal.MarshalText()
+}
+
+// Mul returns d * d2, or an invalid NullDecimal if either operand is invalid.
+//
+// NOTE: this follows SQL NULL propagation, i.e. null is not treated as zero.
+func (d NullDecimal) Mul(d2 NullDecimal) NullDecimal {
+	if !d.Valid || !d2.Valid {
+		return NullDecimal{}
+	}
+	return NewNullDecimal(d.Decimal.Mul(d2.Decimal))
+}
+
+// Ptr returns nil for an invalid NullDecimal, or a pointer to a copy of d.Decimal otherwise.
+func (d NullDecimal) Ptr() *Decimal {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Decimal
+}
+
+func (d *NullDecimal) Scan(value interface{}) error {
+	if value == nil {
+		d.Valid = false
+		return nil
+	}
+	if v, ok := value.(decimal.NullDecimal); ok {
+		if !v.Valid {
+			d.Valid = false
+			return nil
+		}
+		d.Valid = true
+		d.Decimal = NewFromDecimal(v.Decimal)
+		return nil
+	}
+	// empty cells, e.g. of CSV imports or blank CHAR columns, are NULL, same as UnmarshalText.
+	if isBlank(value) {
+		d.Valid = false
+		return nil
+	}
 	d.Valid = true
 	return d.Decimal.Scan(value)
 }
 
+// isBlank reports whether value is a string or bytes of only ASCII whitespace.
+func isBlank(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return len(trimSpace(v)) == 0
+	case []byte:
+		return len(trimSpace(v)) == 0
+	case sql.RawBytes:
+		return len(trimSpace([]byte(v))) == 0
+	}
+	return false
+}
+
+// Sub returns d - d2, or an invalid NullDecimal if either operand is invalid.
+//
+// NOTE: this follows SQL NULL propagation, i.e. null is not treated as zero.
+func (d NullDecimal) Sub(d2 NullDecimal) NullDecimal {
+	if !d.Valid || !d2.Valid {
+		return NullDecimal{}
+	}
+	return NewNullDecimal(d.Decimal.Sub(d2.Decimal))
+}
+
 func (d *NullDecimal) UnmarshalJSON(decimalBytes []byte) error {
 	if string(decimalBytes) == "null" {
 		d.Valid = false
@@ -1100,7 +3242,50 @@ func (d NullDecimal) Value() (driver.Value, error) {
 	return d.Decimal.Value()
 }
 
+// version bytes of the compact binary format, see MarshalBinaryCompact.
+//
+// The decimal.Decimal binary format starts with the big endian uint32 exponent,
+// so these collide only with exponents below -2^31 + 2^25, which are never used in practice.
+const (
+	binaryVersionFixed    byte = 0x81 // followed by the big endian fixed value
+	binaryVersionFallback byte = 0x82 // followed by the decimal.Decimal binary format
+)
+
+// reportFallback calls OnFallback if set, it's small enough to be inlined,
+// so there is no overhead but the nil check when OnFallback is not set.
+func reportFallback(op string) {
+	if OnFallback != nil {
+		OnFallback(op)
+	}
+}
+
 // internal implementation
+var (
+	fallbackOne    = decimal.New(1, 0)
+	fallbackNegOne = decimal.New(-1, 0)
+)
+
+// parse cache used by NewFromStringCached, holds *stringParseCache.
+// it is swapped as a whole on clear, so that in-flight callers
+// never see a partially cleared cache.
+const parseCacheSize = 4096
+
+var parseCache atomic.Value
+
+type stringParseCache struct {
+	m sync.Map // string => Decimal
+	n int64    // number of stored entries, approximately
+}
+
+// optimizeFromDecimal is same as NewFromDecimal, but doesn't report to OnFallback,
+// for operations which report the fallback themselves.
+func optimizeFromDecimal(d decimal.Decimal) Decimal {
+	if fixed, ok := fixedFromDecimal(d); ok {
+		return Decimal{fixed: fixed}
+	}
+	return newFromDecimal(d)
+}
+
 func newFromDecimal(d decimal.Decimal) Decimal {
 	return Decimal{fallback: &d}
 }
@@ -1108,211 +3293,747 @@ func newFromDecimal(d decimal.Decimal) Decimal {
 // sql support
 
 // common example: "0", "0.00", "0.001"
-func parseFixed[T string | []byte](v T) (int64, bool) {
-	// remove quotes if any
-	if len(v) > 2 && v[0] == '"' && v[len(v)-1] == '"' {
-		v = v[1 : len(v)-1]
+//
+// fixedString formats fixed with trailing fractional zeros trimmed,
+// but keeps at least minPlaces fractional digits, minPlaces must be within [0, 12].
+func fixedString(fixed int64, minPlaces int) string {
+	var s [21]byte
+	return string(appendFixedString(s[:0], fixed, minPlaces))
+}
+
+// appendFixedString appends the same as fixedString to b.
+func appendFixedString(b []byte, fixed int64, minPlaces int) []byte {
+	// "-9223372.000000000000" => max length = 21 bytes
+	var s [21]byte
+	start := 7
+	end := 8
+
+	var ufixed uint64
+	if fixed >= 0 {
+		ufixed = uint64(fixed)
+	} else {
+		ufixed = uint64(fixed * -1)
 	}
 
-	// max len of fixed is 21, e.g. -9_223_372.000_000_000_000
-	if len(v) > 21 {
-		return 0, false
-	}
+	integerPart := ufixed / scale
+	fractionalPart := ufixed % scale
 
-	// remove trailing '0' if any (e.g. "0.000")
-	if len(v) > 1 && v[len(v)-1] == '0' {
-		for _, c := range []byte(v) {
-			if c == '.' {
-				for len(v) > 0 && v[len(v)-1] == '0' {
-					v = v[:len(v)-1]
+	// integer part
+	if integerPart == 0 {
+		s[start] = '0'
+	} else {
+		for integerPart >= 10 {
+			s[start] = byte(integerPart%10 + '0')
+			start--
+			integerPart /= 10
+		}
+		s[start] = byte(integerPart + '0')
+	}
+
+	// fractional part
+	if fractionalPart > 0 || minPlaces > 0 {
+		s[8] = '.'
+		for i := 20; i > 8; i-- {
+			is := fractionalPart % 10
+			fractionalPart /= 10
+			if is != 0 || i < 9+minPlaces {
+				s[i] = byte(is + '0')
+				end = i + 1
+				for j := i - 1; j > 8; j-- {
+					s[j] = byte(fractionalPart%10 + '0')
+					fractionalPart /= 10
 				}
 				break
 			}
 		}
 	}
 
-	// remove trailing '.' if any
-	if len(v) > 1 && v[len(v)-1] == '.' {
-		v = v[:len(v)-1]
+	// sign part
+	if fixed < 0 {
+		start -= 1
+		s[start] = '-'
 	}
 
-	negative := false
-	if len(v) > 1 {
-		switch v[0] {
-		case '+':
-			v = v[1:]
-		case '-':
-			v = v[1:]
-			negative = true
+	return append(b, s[start:end]...)
+}
+
+// appendFixed appends fixed with exactly places fractional digits, places must be within [0, 12].
+// fixed must be already rounded to places, remaining digits are truncated.
+func appendFixed(b []byte, fixed int64, places int) []byte {
+	ufixed := uint64(fixed)
+	if fixed < 0 {
+		b = append(b, '-')
+		ufixed = uint64(-fixed)
+	}
+
+	b = strconv.AppendUint(b, ufixed/scale, 10)
+	if places > 0 {
+		var s [precision + 1]byte
+		s[0] = '.'
+		fractionalPart := (ufixed % scale) / uint64(pow10Table[precision-places])
+		for i := places; i > 0; i-- {
+			s[i] = byte(fractionalPart%10 + '0')
+			fractionalPart /= 10
 		}
+		b = append(b, s[:places+1]...)
 	}
+	return b
+}
 
-	if len(v) == 0 {
+// parseFixed accepts the same inputs as decimal.NewFromString,
+// i.e. an optional sign, integer digits, an optional '.' and fractional digits,
+// with at least one digit, and an optional exponent, see parseFixedScientific.
+// any other input should fallback to decimal.Decimal.
+func parseFixed[T string | []byte](v T) (int64, bool) {
+	// max len of fixed is 21, e.g. -9_223_372.000_000_000_000
+	if len(v) > 21 {
+		// trailing zeros of the fractional part don't count,
+		// e.g. "1.230000000000000000000000000000" of a DECIMAL(65, 30) column.
+		v = trimFractionalZeros(v)
+	}
+	if len(v) == 0 || len(v) > 21 {
 		return 0, false
 	}
 
+	original := v
+	negative := false
+	switch v[0] {
+	case '+':
+		v = v[1:]
+	case '-':
+		v = v[1:]
+		negative = true
+	}
+
 	var fixed int64 = 0
+	hasDigits := false
+
+	// integer part
+	i := 0
+	for ; i < len(v) && '0' <= v[i] && v[i] <= '9'; i++ {
+		fixed *= 10
+		fixed += int64(v[i] - '0')
+		if fixed >= maxInt {
+			// out of range
+			return 0, false
+		}
+		hasDigits = true
+	}
 
-	for i, c := range []byte(v) {
-		if '0' <= c && c <= '9' {
-			fixed *= 10
-			fixed += int64(c - '0')
-			if fixed >= maxInt {
-				// out of range
+	if i == len(v) {
+		// no fractional part
+		fixed *= scale
+	} else {
+		if v[i] == 'e' || v[i] == 'E' {
+			return parseFixedScientific(original)
+		}
+		if v[i] != '.' {
+			// invalid case
+			return 0, false
+		}
+
+		// handle fractional part
+		s := v[i+1:]
+
+		// remove trailing '0' if any (e.g. "0.000")
+		for len(s) > 0 && s[len(s)-1] == '0' {
+			s = s[:len(s)-1]
+			hasDigits = true
+		}
+
+		if len(s) > 12 {
+			// out of range
+			return 0, false
+		}
+		for _, c := range []byte(s) {
+			if '0' <= c && c <= '9' {
+				fixed *= 10
+				fixed += int64(c - '0')
+			} else if c == 'e' || c == 'E' {
+				return parseFixedScientific(original)
+			} else {
+				// invalid case
 				return 0, false
 			}
-		} else if c == '.' {
-			// handle fractional part
-			s := v[i+1:]
-			if len(s) > 12 {
+		}
+		if len(s) > 0 {
+			hasDigits = true
+		}
+		fixed *= pow10Table[12-len(s)]
+	}
+
+	if !hasDigits {
+		// invalid case, e.g. "", "-", "."
+		return 0, false
+	}
+
+	if negative {
+		return -fixed, true
+	} else {
+		return fixed, true
+	}
+}
+
+// trimFractionalZeros removes trailing zeros after the decimal point of v,
+// it returns v as is if v has no decimal point or has an exponent.
+func trimFractionalZeros[T string | []byte](v T) T {
+	dot := -1
+	for i := 0; i < len(v); i++ {
+		switch v[i] {
+		case '.':
+			dot = i
+		case 'e', 'E':
+			return v
+		}
+	}
+	if dot < 0 {
+		return v
+	}
+
+	n := len(v)
+	for n > dot+1 && v[n-1] == '0' {
+		n--
+	}
+	return v[:n]
+}
+
+// parseFixedScientific is parseFixed for inputs with exponent, e.g. "1.23E+4", "1.23e-2",
+// where the exponent is an optional sign and digits after the first 'e' or 'E'.
+func parseFixedScientific[T string | []byte](v T) (int64, bool) {
+	e := 0
+	for e < len(v) && v[e] != 'e' && v[e] != 'E' {
+		e++
+	}
+	if e == len(v) {
+		return 0, false
+	}
+
+	// exponent part
+	s := v[e+1:]
+	expNegative := false
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		expNegative = s[0] == '-'
+		s = s[1:]
+	}
+	if len(s) == 0 {
+		// invalid case, e.g. "1e", "1e+"
+		return 0, false
+	}
+	var exp int64
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			// invalid case
+			return 0, false
+		}
+		exp = exp*10 + int64(c-'0')
+		if exp > math.MaxInt32 {
+			// out of range, let fallback decide
+			return 0, false
+		}
+	}
+	if expNegative {
+		exp = -exp
+	}
+
+	// mantissa part
+	m := v[:e]
+	negative := false
+	if len(m) > 0 && (m[0] == '+' || m[0] == '-') {
+		negative = m[0] == '-'
+		m = m[1:]
+	}
+
+	var coef int64
+	hasDigits := false
+	hasPoint := false
+	for _, c := range []byte(m) {
+		switch {
+		case '0' <= c && c <= '9':
+			if coef > (math.MaxInt64-9)/10 {
 				// out of range
 				return 0, false
 			}
-			for _, c := range []byte(s) {
-				if '0' <= c && c <= '9' {
-					fixed *= 10
-					fixed += int64(c - '0')
-				} else {
-					// invalid case
-					return 0, false
-				}
-			}
-			fixed *= pow10Table[12-len(s)]
-			if negative {
-				return -fixed, true
-			} else {
-				return fixed, true
+			coef = coef*10 + int64(c-'0')
+			if hasPoint {
+				exp--
 			}
-		} else {
+			hasDigits = true
+		case c == '.' && !hasPoint:
+			hasPoint = true
+		default:
 			// invalid case
 			return 0, false
 		}
 	}
-	// no fractional part
+	if !hasDigits {
+		// invalid case, e.g. "e1", ".e1"
+		return 0, false
+	}
+	if coef == 0 {
+		return 0, true
+	}
+
+	// fixed = coef * 10^(exp+12)
+	k := exp + precision
+	for k < 0 && coef%10 == 0 {
+		coef /= 10
+		k++
+	}
+	if k < 0 || k >= int64(len(pow10Table)) || coef > maxIntInFixed/pow10Table[k] {
+		// out of range
+		return 0, false
+	}
+
+	fixed := coef * pow10Table[k]
 	if negative {
-		return -fixed * scale, true
-	} else {
-		return fixed * scale, true
+		return -fixed, true
 	}
+	return fixed, true
 }
 
-func (d Decimal) asFallback() decimal.Decimal {
+// trimSpace removes leading and trailing ASCII whitespace, e.g. from padded CHAR columns or CSV fields.
+func trimSpace[T string | []byte](v T) T {
+	for len(v) > 0 && isSpace(v[0]) {
+		v = v[1:]
+	}
+	for len(v) > 0 && isSpace(v[len(v)-1]) {
+		v = v[:len(v)-1]
+	}
+	return v
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// trimTrailingZeros removes trailing zeros of a non-zero coefficient c in place,
+// and returns it with the adjusted exponent.
+func trimTrailingZeros(c *big.Int, exp int32) (*big.Int, int32) {
+	ten := big.NewInt(10)
+	var q, r big.Int
+	for {
+		q.QuoRem(c, ten, &r)
+		if r.Sign() != 0 {
+			return c, exp
+		}
+		c.Set(&q)
+		exp++
+	}
+}
+
+// pow10Big sets z to 10^n and returns z, for n >= 0.
+func pow10Big(z *big.Int, n int32) *big.Int {
+	if int(n) < len(pow10Table) {
+		return z.SetInt64(pow10Table[n])
+	}
+	return z.Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// remove quotes if any, same as decimal.Decimal does for Scan and UnmarshalJSON.
+func unquoteIfQuoted[T string | []byte](v T) T {
+	if len(v) > 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// exact returns d in optimized format, or ErrOutOfRange if it can not be represented.
+// fallback results of in-range values (e.g. from fallback inputs) are converted back.
+func exact(d Decimal) (Decimal, error) {
 	if d.fallback == nil {
-		return decimal.New(d.fixed, -precision)
+		return d, nil
 	}
-	return *d.fallback
+	if fixed, ok := fixedFromDecimal(*d.fallback); ok {
+		return Decimal{fixed: fixed}, nil
+	}
+	return Zero, ErrOutOfRange
 }
 
-func mul(x, y int64) (int64, bool) {
-	if x == 0 || y == 0 {
+// fixedFromDecimal returns fixed representation of d
+// if d is within optimized range with up to 12 precision.
+func fixedFromDecimal(d decimal.Decimal) (int64, bool) {
+	c := d.Coefficient()
+	if c.Sign() == 0 {
 		return 0, true
 	}
 
-	negative := false
-
-	if x < 0 {
-		x = -x
-		negative = !negative
+	e := int(d.Exponent()) + precision
+	if e > 0 {
+		if e >= len(pow10Table) {
+			// out of range
+			return 0, false
+		}
+		c.Mul(c, big.NewInt(pow10Table[e]))
+	} else if e < 0 {
+		// in steps of up to 10^18, e.g. 1 at exponent -32 from adding fallback values with many places.
+		var r big.Int
+		for n := -e; n > 0; {
+			k := n
+			if k >= len(pow10Table) {
+				k = len(pow10Table) - 1
+			}
+			c.QuoRem(c, big.NewInt(pow10Table[k]), &r)
+			if r.Sign() != 0 {
+				// more than 12 precision
+				return 0, false
+			}
+			n -= k
+		}
 	}
 
-	if y < 0 {
-		y = -y
-		negative = !negative
+	if !c.IsInt64() {
+		// out of range
+		return 0, false
 	}
+	fixed := c.Int64()
+	if fixed < minIntInFixed || fixed > maxIntInFixed {
+		// out of range
+		return 0, false
+	}
+	return fixed, true
+}
 
-	// x * y = (x_int + x_fractional) * (y_int + y_fractional)
-	//       = x_int * y_int + x_int * y_fractional
-	//       + x_fractional * y_fractional + x_fractional * y_fractional
+// isPlainNumber returns whether s is formatted as `-?[0-9]+(\.[0-9]+)?`,
+// which is valid as both a JSON number and a JSON string content.
+func isPlainNumber[T string | []byte](s T) bool {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
 
-	x_int := x / scale
-	x_fractional := x % scale
+	digits := 0
+	dot := -1
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.' && dot == -1:
+			dot = i
+		default:
+			return false
+		}
+	}
+	return digits > 0 && dot != 0 && dot != len(s)-1
+}
 
-	y_int := y / scale
-	y_fractional := y % scale
+// equalFixed returns whether d equals to the value represented by fixed.
+func equalFixed(d decimal.Decimal, fixed int64) bool {
+	e := d.Exponent()
+	k := int(e) + precision
+	if k < 0 || k >= len(pow10Table) {
+		return d.Equal(decimal.New(fixed, -precision))
+	}
 
-	var result int64
+	// d = c * 10^e equals fixed * 10^-12 only if fixed = c * 10^k
+	if fixed%pow10Table[k] != 0 {
+		return false
+	}
+	return d.Equal(decimal.New(fixed/pow10Table[k], e))
+}
 
-	if x_int != 0 && y_int != 0 {
-		z := x_int * y_int
-		if z > maxInt {
-			// out of range
-			return 0, false
+// cmpFixed compares the value represented by fixed with d.
+func cmpFixed(fixed int64, d decimal.Decimal) int {
+	if d.Sign() == 0 {
+		// e.g. decimal.Decimal{}, which decimal.Decimal.Cmp would initialize
+		switch {
+		case fixed < 0:
+			return -1
+		case fixed > 0:
+			return 1
+		default:
+			return 0
 		}
-		result = z * scale
 	}
 
-	if x_fractional != 0 && y_fractional != 0 {
-		// x_fractional * y_fractional = x_fractional_a * y_fractional_a
-		//                             + x_fractional_a * y_fractional_b
-		//                             + x_fractional_b * y_fractional_a
-		//                             + x_fractional_b * y_fractional_b
-		x_fractional_a := x_fractional / 1000_000
-		x_fractional_b := x_fractional % 1000_000
-		y_fractional_a := y_fractional / 1000_000
-		y_fractional_b := y_fractional % 1000_000
+	e := d.Exponent()
+	k := int(e) + precision
+	if k < 0 || k >= len(pow10Table) {
+		return decimal.New(fixed, -precision).Cmp(d)
+	}
 
-		s := x_fractional_a * y_fractional_a
+	// fixed * 10^-12 = q * 10^e + r * 10^-12, where |r * 10^-12| < 10^e,
+	// so it is decided by q * 10^e unless that equals d, which is a multiple of 10^e as well.
+	q, r := fixed/pow10Table[k], fixed%pow10Table[k]
+	if c := decimal.New(q, e).Cmp(d); c != 0 || r == 0 {
+		return c
+	}
+	if r > 0 {
+		return 1
+	}
+	return -1
+}
 
-		if x_fractional_b != 0 || y_fractional_b != 0 {
-			p1 := x_fractional_a*y_fractional_b + x_fractional_b*y_fractional_a
-			p2 := x_fractional_b * y_fractional_b
+func fixedFromFloat(f float64) (int64, bool) {
+	picoFloat := f * float64(scale)
+	picoInt64 := int64(picoFloat)
 
-			if p1%1000_000 != 0 || p2%scale != 0 {
-				// out of range
-				return 0, false
-			}
+	// check if it's within range and is whole number
+	// integer overflow is accounted for via the `picoFloat == float64(picoInt64)` check
+	if picoInt64 >= minIntInFixed && picoInt64 <= maxIntInFixed && picoFloat == float64(picoInt64) {
+		return picoInt64, true
+	}
+	return 0, false
+}
 
-			s += p1/1000_000 + p2/scale
-		}
+func (d Decimal) asFallback() decimal.Decimal {
+	if d.fallback == nil {
+		return decimal.New(d.fixed, -precision)
+	}
+	return *d.fallback
+}
 
-		if result <= maxIntInFixed-s {
-			result += s
-		} else {
-			// out of range
-			return 0, false
-		}
+// mul returns x * y in fixed format, i.e. x * y / 10^12 computed as 128 bits product,
+// it fails if the product has more than 12 precision or is out of range.
+func mul(x, y int64) (int64, bool) {
+	negative := (x < 0) != (y < 0)
+	ux, uy := absFixed(x), absFixed(y)
+
+	hi, lo := bits.Mul64(ux, uy)
+	if hi >= scale {
+		// quotient overflows uint64
+		return 0, false
+	}
+	q, r := bits.Div64(hi, lo, scale)
+	if r != 0 || q > uint64(maxIntInFixed) {
+		// more than 12 precision or out of range
+		return 0, false
 	}
+	if negative {
+		return -int64(q), true
+	}
+	return int64(q), true
+}
 
-	if x_int != 0 && y_fractional != 0 {
-		p := x_int * y_fractional
-		if result <= maxIntInFixed-p {
-			result += p
-		} else {
-			// out of range
-			return 0, false
-		}
+// absFixed returns |fixed| as uint64, which can't overflow.
+func absFixed(fixed int64) uint64 {
+	if fixed < 0 {
+		return uint64(-fixed)
 	}
+	return uint64(fixed)
+}
 
-	if x_fractional != 0 && y_int != 0 {
-		p := x_fractional * y_int
-		if result <= maxIntInFixed-p {
-			result += p
-		} else {
-			// out of range
-			return 0, false
+// divRound returns x / y rounded with the given rounding mode to places decimal places in fixed format,
+// y must not be zero and places must be within [0, 12].
+func divRound(x, y int64, places int, mode RoundingMode) (int64, bool) {
+	negative := (x < 0) != (y < 0)
+	ux, uy := absFixed(x), absFixed(y)
+
+	// x / y * 10^places as 128 bits division
+	hi, lo := bits.Mul64(ux, uint64(pow10Table[places]))
+	if hi >= uy {
+		// quotient overflows uint64
+		return 0, false
+	}
+	q, r := bits.Div64(hi, lo, uy)
+	if r != 0 {
+		c := 0
+		if r > uy-r {
+			c = 1
+		} else if r < uy-r {
+			c = -1
 		}
+		if roundAwayFromZero(mode, negative, c, q%2 == 1) {
+			q++
+		}
+	}
+
+	s := uint64(pow10Table[precision-places])
+	if q > uint64(maxIntInFixed)/s {
+		return 0, false
+	}
+	if negative {
+		return -int64(q * s), true
+	}
+	return int64(q * s), true
+}
+
+// fma returns x * y + z in fixed format, where x * y is computed in 128 bits,
+// it fails if x * y has more than 12 precision or the result is out of range.
+func fma(x, y, z int64) (int64, bool) {
+	negative := (x < 0) != (y < 0)
+	ux, uy := absFixed(x), absFixed(y)
+
+	hi, lo := bits.Mul64(ux, uy)
+	if hi >= scale {
+		// quotient overflows uint64
+		return 0, false
+	}
+	q, r := bits.Div64(hi, lo, scale)
+	if r != 0 {
+		// more than 12 precision
+		return 0, false
 	}
 
+	// z +/- q as 128 bits, z sign extended
+	zhi := uint64(z >> 63)
+	var sum, c uint64
 	if negative {
-		result *= -1
+		sum, c = bits.Sub64(uint64(z), q, 0)
+		zhi -= c
+	} else {
+		sum, c = bits.Add64(uint64(z), q, 0)
+		zhi += c
 	}
 
-	return result, true
+	if int64(zhi) != int64(sum)>>63 {
+		// out of int64 range
+		return 0, false
+	}
+	fixed := int64(sum)
+	if fixed < minIntInFixed || fixed > maxIntInFixed {
+		return 0, false
+	}
+	return fixed, true
 }
 
-func div(x, y int64) (int64, bool) {
-	if x == 0 {
-		return 0, y != 0
+// mulRound returns x * y / scale rounded half away from zero to a multiple of s,
+// where s divides scale. it returns false if the result is out of optimized range.
+func mulRound(x, y, s int64) (int64, bool) {
+	negative := (x < 0) != (y < 0)
+	ux, uy := absFixed(x), absFixed(y)
+
+	// 128 bits product, then truncated division by scale
+	hi, lo := bits.Mul64(ux, uy)
+	if hi >= scale {
+		// quotient overflows uint64
+		return 0, false
+	}
+	q, r := bits.Div64(hi, lo, scale)
+	if q > uint64(maxIntInFixed+s) {
+		return 0, false
 	}
 
-	fz := float64(x) / float64(y)
-	z := int64(fz * scale)
+	// when s > 1, half of s is a whole number in q,
+	// so the truncated remainder r can not change the rounding direction.
+	if s == 1 {
+		if r*2 >= scale {
+			q++
+		}
+	} else if m := q % uint64(s); m != 0 {
+		q -= m
+		if m*2 >= uint64(s) {
+			q += uint64(s)
+		}
+	}
 
-	// this `mul` check is to ensure we do not
-	// lose precision from previous float64 operations.
-	if xx, ok := mul(y, z); ok && x == xx {
-		return z, true
-	} else {
+	if q > uint64(maxIntInFixed) {
+		return 0, false
+	}
+	if negative {
+		return -int64(q), true
+	}
+	return int64(q), true
+}
+
+// div returns x / y in fixed format if the quotient is exact within 12 precision,
+// computed as 128 bits division, i.e. x * 10^12 / y.
+func div(x, y int64) (int64, bool) {
+	if y == 0 {
+		return 0, false
+	}
+	negative := (x < 0) != (y < 0)
+	ux, uy := absFixed(x), absFixed(y)
+
+	hi, lo := bits.Mul64(ux, scale)
+	if hi >= uy {
+		// quotient overflows uint64
 		return 0, false
 	}
+	q, r := bits.Div64(hi, lo, uy)
+	if r != 0 || q > uint64(maxIntInFixed) {
+		// more than 12 precision or out of range
+		return 0, false
+	}
+	if negative {
+		return -int64(q), true
+	}
+	return int64(q), true
+}
+
+// roundAwayFromZero reports whether an inexact truncated quotient q is rounded away from zero
+// with the given rounding mode, where c compares the remainder to half of the divisor (-1, 0, 1).
+func roundAwayFromZero(mode RoundingMode, negative bool, c int, odd bool) bool {
+	switch mode {
+	case RoundDown:
+		return false
+	case RoundUp:
+		return true
+	case RoundCeil:
+		return !negative
+	case RoundFloor:
+		return negative
+	}
+
+	if c != 0 {
+		return c > 0
+	}
+
+	// exactly half
+	switch mode {
+	case RoundHalfEven:
+		return odd
+	case RoundHalfUp:
+		return !negative
+	default:
+		return true
+	}
+}
+
+// roundFixed rounds fixed to a multiple of s with the given rounding mode.
+// s must be positive, and fixed - fixed%s ± s must not overflow.
+func roundFixed(fixed int64, s int64, mode RoundingMode) int64 {
+	m := fixed % s
+	if m == 0 {
+		// no need to round
+		return fixed
+	}
+
+	// toZero and awayFromZero are the two candidates around fixed.
+	toZero := fixed - m
+	awayFromZero := toZero + s
+	if m < 0 {
+		awayFromZero = toZero - s
+	}
+
+	switch mode {
+	case RoundDown:
+		return toZero
+	case RoundUp:
+		return awayFromZero
+	case RoundCeil:
+		if m > 0 {
+			return awayFromZero
+		}
+		return toZero
+	case RoundFloor:
+		if m < 0 {
+			return awayFromZero
+		}
+		return toZero
+	}
+
+	if m < 0 {
+		m = -m
+	}
+	if m*2 > s {
+		return awayFromZero
+	}
+	if m*2 < s {
+		return toZero
+	}
+
+	// exactly half
+	switch mode {
+	case RoundHalfEven:
+		if (toZero/s)%2 == 0 {
+			return toZero
+		}
+		return awayFromZero
+	case RoundHalfUp:
+		if fixed > 0 {
+			return awayFromZero
+		}
+		return toZero
+	default:
+		return awayFromZero
+	}
 }
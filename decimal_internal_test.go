@@ -0,0 +1,317 @@
+package alpacadecimal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// tests in this file construct internal state directly,
+// which is not possible through the public API.
+
+func TestMalformedFixed(t *testing.T) {
+	t.Run("Decimal.Neg", func(t *testing.T) {
+		x := Decimal{fixed: math.MinInt64}
+		require.Equal(t, "9223372.036854775808", x.Neg().String())
+
+		y := Decimal{fixed: minIntInFixed}
+		require.Equal(t, "9223372", y.Neg().String())
+		require.True(t, y.Neg().IsOptimized())
+	})
+
+	t.Run("Decimal.Abs", func(t *testing.T) {
+		x := Decimal{fixed: math.MinInt64}
+		require.Equal(t, "9223372.036854775808", x.Abs().String())
+
+		y := Decimal{fixed: minIntInFixed}
+		require.Equal(t, "9223372", y.Abs().String())
+		require.True(t, y.Abs().IsOptimized())
+	})
+
+	t.Run("Decimal.Sub", func(t *testing.T) {
+		x := Decimal{fixed: math.MinInt64}
+		require.Equal(t, "9223372.036854775808", Zero.Sub(x).String())
+		require.Equal(t, "0.036854775808", Decimal{fixed: minIntInFixed}.Sub(x).String())
+
+		y := Decimal{fixed: minIntInFixed}
+		require.Equal(t, "9223372", Zero.Sub(y).String())
+		require.True(t, Zero.Sub(y).IsOptimized())
+	})
+}
+
+func TestEqualMixed(t *testing.T) {
+	fallback := func(value int64, exp int32) Decimal {
+		return newFromDecimal(decimal.New(value, exp))
+	}
+
+	cases := []struct {
+		x        Decimal
+		y        Decimal
+		expected bool
+	}{
+		{NewFromInt(0), fallback(0, 5), true},
+		{NewFromInt(0), fallback(0, -20), true},
+		{NewFromInt(12), fallback(12, 0), true},
+		{NewFromInt(12), fallback(120, -1), true},
+		{NewFromInt(1200), fallback(12, 2), true},
+		{NewFromInt(1200), fallback(13, 2), false},
+		{NewFromInt(1234), fallback(12, 2), false},
+		{NewFromFloat(-1.5), fallback(-15, -1), true},
+		{NewFromFloat(-1.5), fallback(15, -1), false},
+		{NewFromFloat(1.5), fallback(1500000000000000, -15), true},
+		{NewFromFloat(1.5), fallback(1500000000000001, -15), false},
+		{NewFromInt(1), fallback(1, -12), false},
+		{NewFromInt(maxInt), fallback(maxInt, 0), true},
+		{NewFromInt(maxInt), fallback(maxInt, 20), false},
+		{NewFromInt(maxInt), fallback(maxInt*10, 0), false},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, c.x.Equal(c.y), "%s == %s", c.x, c.y)
+		require.Equal(t, c.expected, c.y.Equal(c.x), "%s == %s", c.y, c.x)
+		require.Equal(t, c.expected, c.x.asFallback().Equal(c.y.asFallback()), "%s == %s", c.x, c.y)
+	}
+}
+
+// TestEqualRepresentations compares every value in every representation with each other,
+// i.e. optimized, fallback at its own exponent, with extra trailing zeros, and with trailing zeros removed.
+func TestEqualRepresentations(t *testing.T) {
+	values := []string{
+		"0", "1", "-1", "1.5", "-1.5", "100", "1000000", "123456.789", "-123456.789",
+		"0.000000000001", "-0.000000000001", "0.000000000002", "9223372", "-9223372", "9223371.999999999999",
+	}
+
+	representations := func(v string) []Decimal {
+		dd := decimal.RequireFromString(v)
+		rs := []Decimal{
+			newFromDecimal(dd),
+			newFromDecimal(decimal.NewFromBigInt(trimTrailingZerosOrZero(dd))),
+		}
+		if fixed, ok := parseFixed(v); ok {
+			rs = append(rs, Decimal{fixed: fixed})
+		}
+		for _, k := range []int32{1, 5, 12, 13, 25} {
+			c := new(big.Int).Mul(dd.Coefficient(), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(k)), nil))
+			rs = append(rs, newFromDecimal(decimal.NewFromBigInt(c, dd.Exponent()-k)))
+		}
+		if dd.IsZero() {
+			rs = append(rs, newFromDecimal(decimal.Decimal{}), newFromDecimal(decimal.New(0, 10)), newFromDecimal(decimal.New(0, -30)))
+		}
+		return rs
+	}
+
+	for _, v := range values {
+		for _, v2 := range values {
+			expected := decimal.RequireFromString(v).Cmp(decimal.RequireFromString(v2))
+
+			for _, x := range representations(v) {
+				for _, y := range representations(v2) {
+					msg := fmt.Sprintf("%s (%d, %v) vs %s (%d, %v)", x, x.Exponent(), x.IsOptimized(), y, y.Exponent(), y.IsOptimized())
+					require.Equal(t, expected == 0, x.Equal(y), msg)
+					require.Equal(t, expected, x.Cmp(y), msg)
+					require.Equal(t, expected, Compare(x, y), msg)
+					require.Equal(t, expected == 0, x.EqualDecimal(y.asFallback()), msg)
+					require.Equal(t, expected, x.CmpDecimal(y.asFallback()), msg)
+					require.Equal(t, expected < 0, x.LessThan(y), msg)
+					require.Equal(t, expected > 0, x.GreaterThan(y), msg)
+				}
+			}
+		}
+	}
+
+	// mixed comparison doesn't allocate when the fallback exponent is within [-12, 6]
+	x := RequireFromString("1.5")
+	for _, y := range []Decimal{newFromDecimal(decimal.New(15, -1)), newFromDecimal(decimal.Decimal{}), newFromDecimal(decimal.New(15, 3))} {
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = x.Equal(y)
+			_ = x.Cmp(y)
+			_ = y.Cmp(x)
+		})
+		require.Equal(t, float64(0), allocs, y.String())
+	}
+}
+
+// trimTrailingZerosOrZero is trimTrailingZeros of the coefficient of d, which also handles zero.
+func trimTrailingZerosOrZero(d decimal.Decimal) (*big.Int, int32) {
+	if d.IsZero() {
+		return new(big.Int), 0
+	}
+	return trimTrailingZeros(d.Coefficient(), d.Exponent())
+}
+
+func TestDiv(t *testing.T) {
+	check := func(x, y int64) {
+		z, ok := div(x, y)
+
+		// x * 10^12 / y
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(x), big.NewInt(scale)), big.NewInt(y), new(big.Int))
+		expected := r.Sign() == 0 && q.IsInt64() && q.Int64() >= minIntInFixed && q.Int64() <= maxIntInFixed
+		require.Equal(t, expected, ok, "%d / %d", x, y)
+		if ok {
+			require.Equal(t, q.Int64(), z, "%d / %d", x, y)
+		}
+	}
+
+	values := []int64{
+		1, 2, 5, 8, 16, 3, 7, 1000, scale, 2 * scale, 3 * scale, 125 * scale / 1000,
+		maxIntInFixed, maxIntInFixed - 1, maxIntInFixed / 2, maxIntInFixed / 3, 6847245802246229650, 3106919167034404512,
+		math.MaxInt64, math.MinInt64 + 1,
+	}
+	for _, x := range values {
+		for _, y := range values {
+			check(x, y)
+			check(-x, y)
+			check(x, -y)
+			check(-x, -y)
+		}
+		check(0, x)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100_000; i++ {
+		x := rng.Int63n(maxIntInFixed)
+		y := []int64{2, 4, 8, 16, 5, 25, 7, 3 * scale, 8 * scale, rng.Int63n(maxIntInFixed) + 1}[rng.Intn(10)]
+		check(x, y)
+		check(x-x%y, y)
+	}
+
+	_, ok := div(1, 0)
+	require.False(t, ok)
+	_, ok = div(0, 0)
+	require.False(t, ok)
+}
+
+func TestMul(t *testing.T) {
+	check := func(x, y int64) {
+		z, ok := mul(x, y)
+
+		// x * y / 10^12
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(x), big.NewInt(y)), big.NewInt(scale), new(big.Int))
+		expected := r.Sign() == 0 && q.IsInt64() && q.Int64() >= minIntInFixed && q.Int64() <= maxIntInFixed
+		require.Equal(t, expected, ok, "%d * %d", x, y)
+		if ok {
+			require.Equal(t, q.Int64(), z, "%d * %d", x, y)
+		}
+	}
+
+	values := []int64{
+		0, 1, 2, 1000, 1_000_000, scale, 2 * scale, scale / 2,
+		maxIntInFixed, maxIntInFixed - 1, maxIntInFixed / 2, 3037000499000000000, 3037000499,
+		math.MaxInt64, math.MinInt64 + 1,
+	}
+	for _, x := range values {
+		for _, y := range values {
+			check(x, y)
+			check(-x, y)
+			check(x, -y)
+			check(-x, -y)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100_000; i++ {
+		x := rng.Int63n(maxIntInFixed)
+		y := rng.Int63n(maxIntInFixed)
+		switch rng.Intn(3) {
+		case 0:
+			y = y / scale * scale
+		case 1:
+			x, y = x%(scale*1000), y%(scale*1000)/1_000_000*1_000_000
+		}
+		check(x, y)
+		check(-x, y)
+	}
+}
+
+func TestIsPlainNumber(t *testing.T) {
+	for _, s := range []string{"0", "-0", "1.5", "-123.456", "9223372.036854775807"} {
+		require.True(t, isPlainNumber(s), s)
+	}
+	for _, s := range []string{"", "-", ".", "1.", ".5", "-.5", "1.2.3", "1e3", "NaN", "-Inf", "\"1\"", "1 "} {
+		require.False(t, isPlainNumber(s), s)
+	}
+}
+
+// fallback-tagged values can hold numbers in the optimized range, e.g. results of
+// fallback arithmetic, including zero with any exponent and the zero value of decimal.Decimal.
+func TestFallbackSign(t *testing.T) {
+	cases := []struct {
+		d    Decimal
+		sign int
+	}{
+		{newFromDecimal(decimal.Decimal{}), 0},
+		{newFromDecimal(decimal.New(0, -20)), 0},
+		{newFromDecimal(decimal.New(0, 5)), 0},
+		{newFromDecimal(decimal.RequireFromString("123456789.5").Sub(decimal.RequireFromString("123456789.5"))), 0},
+		{RequireFromString("123456789.5").Mul(Zero), 0},
+		{newFromDecimal(decimal.New(-1, -20)), -1},
+		{newFromDecimal(decimal.New(-15, -1)), -1},
+		{newFromDecimal(decimal.New(1, -20)), 1},
+		{newFromDecimal(decimal.New(1, 20)), 1},
+	}
+
+	for _, c := range cases {
+		d := c.d
+		require.False(t, d.IsOptimized(), d.String())
+
+		require.Equal(t, c.sign, d.Sign(), d.String())
+		require.Equal(t, c.sign == 0, d.IsZero(), d.String())
+		require.Equal(t, c.sign < 0, d.IsNegative(), d.String())
+		require.Equal(t, c.sign > 0, d.IsPositive(), d.String())
+		require.Equal(t, c.sign >= 0, d.IsNonNegative(), d.String())
+		require.Equal(t, c.sign <= 0, d.IsNonPositive(), d.String())
+
+		require.Equal(t, c.sign, d.Cmp(Zero), d.String())
+		require.Equal(t, -c.sign, Zero.Cmp(d), d.String())
+		require.Equal(t, c.sign == 0, d.Equal(Zero), d.String())
+		require.Equal(t, c.sign == 0, Zero.Equal(d), d.String())
+
+		require.Equal(t, -c.sign, d.Neg().Sign(), d.String())
+		require.Equal(t, c.sign*c.sign, d.Abs().Sign(), d.String())
+		if c.sign == 0 {
+			require.Equal(t, "0", d.String())
+			require.Equal(t, "0", d.Neg().String())
+		}
+	}
+}
+
+func TestParseCache(t *testing.T) {
+	ClearParseCache()
+	defer ClearParseCache()
+
+	count := func() int {
+		n := 0
+		parseCache.Load().(*stringParseCache).m.Range(func(_, _ any) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	_, err := NewFromStringCached("error")
+	require.Error(t, err)
+	require.Equal(t, 0, count())
+
+	for i := 0; i < parseCacheSize+100; i++ {
+		_, err := NewFromStringCached(strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	require.Equal(t, parseCacheSize, count())
+
+	ClearParseCache()
+	require.Equal(t, 0, count())
+}
+
+// BenchmarkInitCache measures the startup cost of building the cache at init,
+// which is skipped with `ALPACADECIMAL_LAZY_CACHE=1`.
+func BenchmarkInitCache(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		initCache()
+	}
+}
@@ -1,9 +1,18 @@
 package alpacadecimal_test
 
 import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/alpacahq/alpacadecimal"
@@ -72,14 +81,289 @@ func TestDecimal(t *testing.T) {
 		shouldEqual(t, d2, two)
 	})
 
+	t.Run("AlignScale", func(t *testing.T) {
+		places, aligned := alpacadecimal.AlignScale(nil)
+		require.Equal(t, int32(0), places)
+		require.Empty(t, aligned)
+
+		places, aligned = alpacadecimal.AlignScale([]alpacadecimal.Decimal{
+			alpacadecimal.RequireFromString("1.50"),
+			alpacadecimal.NewFromInt(-2),
+			alpacadecimal.RequireFromString("0.125"),
+		})
+		require.Equal(t, int32(3), places)
+		require.Equal(t, []string{"1.500", "-2.000", "0.125"}, aligned)
+
+		places, aligned = alpacadecimal.AlignScale([]alpacadecimal.Decimal{
+			alpacadecimal.NewFromInt(100),
+			alpacadecimal.RequireFromString("123456789.10"),
+			alpacadecimal.RequireFromString("0.00000000000010"),
+		})
+		require.Equal(t, int32(13), places)
+		require.Equal(t, []string{"100.0000000000000", "123456789.1000000000000", "0.0000000000001"}, aligned)
+
+		// same as StringFixed with the max places
+		ds := make([]alpacadecimal.Decimal, len(cases))
+		for i, c := range cases {
+			ds[i] = alpacadecimal.RequireFromString(c)
+		}
+		places, aligned = alpacadecimal.AlignScale(ds)
+		for i, d := range ds {
+			require.Equal(t, d.StringFixed(places), aligned[i])
+		}
+	})
+
 	t.Run("Avg", func(t *testing.T) {
 		shouldEqual(t, alpacadecimal.Avg(one, two, three), two)
 	})
 
+	t.Run("AvgSafe", func(t *testing.T) {
+		{
+			x, err := alpacadecimal.AvgSafe([]alpacadecimal.Decimal{one, two, three})
+			require.NoError(t, err)
+			shouldEqual(t, x, two)
+			require.True(t, x.IsOptimized())
+		}
+
+		{
+			x, err := alpacadecimal.AvgSafe([]alpacadecimal.Decimal{one, two})
+			require.NoError(t, err)
+			require.Equal(t, "1.5", x.String())
+		}
+
+		{
+			x, err := alpacadecimal.AvgSafe([]alpacadecimal.Decimal{one, one, two})
+			require.NoError(t, err)
+			require.Equal(t, alpacadecimal.Avg(one, one, two).String(), x.String())
+		}
+
+		{
+			// sum is out of optimized range, but the average is not.
+			large := alpacadecimal.RequireFromString("9000000.5")
+			x, err := alpacadecimal.AvgSafe([]alpacadecimal.Decimal{large, large, large, large})
+			require.NoError(t, err)
+			shouldEqual(t, x, large)
+			require.True(t, x.IsOptimized())
+			require.False(t, alpacadecimal.Avg(large, large, large, large).IsOptimized())
+		}
+
+		{
+			x, err := alpacadecimal.AvgSafe([]alpacadecimal.Decimal{alpacadecimal.NewFromInt(123456789), alpacadecimal.NewFromInt(-123456789), three})
+			require.NoError(t, err)
+			shouldEqual(t, x, one)
+		}
+
+		{
+			_, err := alpacadecimal.AvgSafe(nil)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("Compare", func(t *testing.T) {
+		require.Equal(t, -1, alpacadecimal.Compare(one, two))
+		require.Equal(t, 0, alpacadecimal.Compare(one, one))
+		require.Equal(t, 1, alpacadecimal.Compare(three, one))
+
+		xs := []alpacadecimal.Decimal{three, alpacadecimal.NewFromInt(123456789), one, alpacadecimal.NewFromInt(-1), two}
+		sort.Slice(xs, func(i, j int) bool { return alpacadecimal.Compare(xs[i], xs[j]) < 0 })
+		require.Equal(t, "-1,1,2,3,123456789", fmt.Sprintf("%s,%s,%s,%s,%s", xs[0], xs[1], xs[2], xs[3], xs[4]))
+
+		requireCompatible2(t, func(input1, input2 string) (int, int) {
+			x := alpacadecimal.Compare(alpacadecimal.RequireFromString(input1), alpacadecimal.RequireFromString(input2))
+			y := decimal.RequireFromString(input1).Cmp(decimal.RequireFromString(input2))
+			return x, y
+		})
+	})
+
+	t.Run("Distinct", func(t *testing.T) {
+		require.Nil(t, alpacadecimal.Distinct(nil))
+
+		var xs []alpacadecimal.Decimal
+		for _, input := range []string{"1.0", "2", "1.00", "-0", "0", "12345678901234567890.50", "1", "12345678901234567890.5", "-1", "0.000000000000001", "1E-15"} {
+			xs = append(xs, alpacadecimal.RequireFromString(input))
+		}
+		xs = append(xs, alpacadecimal.NewFromDecimal(decimal.RequireFromString("2.000")), alpacadecimal.NewFromInt(2))
+
+		ds := alpacadecimal.Distinct(xs)
+		require.Len(t, ds, 6)
+		require.Equal(t, "1,2,0,12345678901234567890.5,-1,0.000000000000001", fmt.Sprintf("%s,%s,%s,%s,%s,%s", ds[0], ds[1], ds[2], ds[3], ds[4], ds[5]))
+		require.Equal(t, int32(-2), ds[3].Exponent(), "first occurrence is kept")
+
+		for i := range ds {
+			for j := i + 1; j < len(ds); j++ {
+				require.False(t, ds[i].Equal(ds[j]))
+			}
+		}
+	})
+
+	t.Run("ParsePrefix", func(t *testing.T) {
+		for _, c := range []struct {
+			input     string
+			expected  string
+			n         int
+			optimized bool
+		}{
+			{"1.5", "1.5", 3, true},
+			{"1.5;2.5", "1.5", 3, true},
+			{"-2.5.1", "-2.5", 4, true},
+			{"+0.25 USD", "0.25", 5, true},
+			{"123\x00\x01", "123", 3, true},
+			{"1e3", "1", 1, true},
+			{"1.", "1", 2, true},
+			{".5,", "0.5", 2, true},
+			{"-.5--", "-0.5", 3, true},
+			{"007|", "7", 3, true},
+			{"0.0000000000001|", "0.0000000000001", 15, false},
+			{"12345678901234567890.5\n", "12345678901234567890.5", 22, false},
+			{"1.230000000000000000000000000000]", "1.23", 32, true},
+		} {
+			d, n, err := alpacadecimal.ParsePrefix([]byte(c.input))
+			require.NoError(t, err, c.input)
+			require.Equal(t, c.expected, d.String(), c.input)
+			require.Equal(t, c.n, n, c.input)
+			require.Equal(t, c.optimized, d.IsOptimized(), c.input)
+
+			// same as parsing the token alone
+			shouldEqual(t, alpacadecimal.RequireFromString(c.input[:n]), d)
+		}
+
+		for _, input := range []string{"", "-", "+", ".", "-.", "abc", " 1", "e3", "--1"} {
+			_, n, err := alpacadecimal.ParsePrefix([]byte(input))
+			require.Error(t, err, input)
+			require.Equal(t, 0, n, input)
+		}
+
+		// consecutive decimals in one buffer
+		b := []byte("1.5,-2,0.000000000001,12345678901234567890")
+		var ds []string
+		for len(b) > 0 {
+			d, n, err := alpacadecimal.ParsePrefix(b)
+			require.NoError(t, err)
+			ds = append(ds, d.String())
+			b = b[n:]
+			if len(b) > 0 {
+				require.Equal(t, byte(','), b[0])
+				b = b[1:]
+			}
+		}
+		require.Equal(t, []string{"1.5", "-2", "0.000000000001", "12345678901234567890"}, ds)
+
+		buf := []byte("1.5;")
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _, _ = alpacadecimal.ParsePrefix(buf)
+		})
+		require.Equal(t, float64(0), allocs)
+	})
+
+	t.Run("PercentChange", func(t *testing.T) {
+		for _, c := range []struct {
+			from, to string
+			ratio    string
+			pct      string
+		}{
+			{"100", "125", "0.25", "25"},
+			{"125", "100", "-0.2", "-20"},
+			{"3", "4", "0.3333333333333333", "33.33333333333333"},
+			{"-2", "1", "-1.5", "-150"},
+			{"1.5", "1.5", "0", "0"},
+			{"0.000001", "1", "999999", "99999900"},
+			{"12345678901234567890", "24691357802469135780", "1", "100"},
+		} {
+			from, to := alpacadecimal.RequireFromString(c.from), alpacadecimal.RequireFromString(c.to)
+
+			ratio, err := alpacadecimal.PercentChange(from, to)
+			require.NoError(t, err)
+			require.Equal(t, c.ratio, ratio.String(), c)
+
+			pct, err := alpacadecimal.PercentChangePct(from, to)
+			require.NoError(t, err)
+			require.Equal(t, c.pct, pct.String(), c)
+
+			expected := decimal.RequireFromString(c.to).Sub(decimal.RequireFromString(c.from)).Div(decimal.RequireFromString(c.from))
+			require.Equal(t, expected.String(), ratio.String(), c)
+		}
+
+		_, err := alpacadecimal.PercentChange(alpacadecimal.Zero, one)
+		require.Error(t, err)
+		_, err = alpacadecimal.PercentChangePct(alpacadecimal.RequireFromString("0.00"), one)
+		require.Error(t, err)
+	})
+
+	t.Run("Pow10", func(t *testing.T) {
+		require.Equal(t, "1", alpacadecimal.Pow10(0).String())
+		require.Equal(t, "100", alpacadecimal.Pow10(2).String())
+		require.Equal(t, "0.01", alpacadecimal.Pow10(-2).String())
+
+		for n := int32(-20); n <= 20; n++ {
+			x := alpacadecimal.Pow10(n)
+			require.Equal(t, n >= -12 && n <= 6, x.IsOptimized(), n)
+			require.Equal(t, decimal.New(1, n).String(), x.String(), n)
+			shouldEqual(t, alpacadecimal.New(1, n), x)
+		}
+
+		// boundaries
+		require.Equal(t, "1000000", alpacadecimal.Pow10(6).String())
+		require.Equal(t, "10000000", alpacadecimal.Pow10(7).String())
+		require.Equal(t, "0.000000000001", alpacadecimal.Pow10(-12).String())
+		require.Equal(t, "0.0000000000001", alpacadecimal.Pow10(-13).String())
+	})
+
+	t.Run("Add & Sub & Mul & Div", func(t *testing.T) {
+		fold := func(f func(a, b alpacadecimal.Decimal) alpacadecimal.Decimal, init alpacadecimal.Decimal, xs ...alpacadecimal.Decimal) alpacadecimal.Decimal {
+			result := init
+			for _, x := range xs {
+				result = f(result, x)
+			}
+			return result
+		}
+
+		require.Equal(t, "6", fold(alpacadecimal.Add, alpacadecimal.Zero, one, two, three).String())
+		require.Equal(t, "-6", fold(alpacadecimal.Sub, alpacadecimal.Zero, one, two, three).String())
+		require.Equal(t, "6", fold(alpacadecimal.Mul, one, one, two, three).String())
+		require.Equal(t, "0.5", fold(alpacadecimal.Div, three, two, three).String())
+
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			a, b := alpacadecimal.RequireFromString(input1), alpacadecimal.RequireFromString(input2)
+			return alpacadecimal.Add(a, b).String() + alpacadecimal.Sub(a, b).String() + alpacadecimal.Mul(a, b).String(),
+				a.Add(b).String() + a.Sub(b).String() + a.Mul(b).String()
+		})
+	})
+
 	t.Run("Max", func(t *testing.T) {
 		require.True(t, alpacadecimal.Max(one, two, three).Equal(three))
 	})
 
+	t.Run("MaxIndex & MinIndex", func(t *testing.T) {
+		{
+			x, i := alpacadecimal.MaxIndex(nil)
+			require.Equal(t, -1, i)
+			require.True(t, x.IsZero())
+
+			y, j := alpacadecimal.MinIndex([]alpacadecimal.Decimal{})
+			require.Equal(t, -1, j)
+			require.True(t, y.IsZero())
+		}
+
+		large := alpacadecimal.RequireFromString("123456789.5")
+		ds := []alpacadecimal.Decimal{two, one, three, large.Neg(), three, large, one, large.Neg(), large}
+
+		x, i := alpacadecimal.MaxIndex(ds)
+		require.Equal(t, 5, i)
+		require.True(t, x.Equal(large))
+
+		y, j := alpacadecimal.MinIndex(ds)
+		require.Equal(t, 3, j)
+		require.True(t, y.Equal(large.Neg()))
+
+		x, i = alpacadecimal.MaxIndex(ds[:5])
+		require.Equal(t, 2, i)
+		require.True(t, x.Equal(alpacadecimal.Max(ds[0], ds[1:5]...)))
+
+		y, j = alpacadecimal.MinIndex(ds[:3])
+		require.Equal(t, 1, j)
+		require.True(t, y.Equal(alpacadecimal.Min(ds[0], ds[1:3]...)))
+	})
+
 	t.Run("Min", func(t *testing.T) {
 		require.True(t, alpacadecimal.Min(one, two, three).Equal(one))
 	})
@@ -161,6 +445,17 @@ func TestDecimal(t *testing.T) {
 		require.Equal(t, x.String(), y.String())
 	})
 
+	t.Run("NewFromDecimal", func(t *testing.T) {
+		requireCompatible(t, func(input string) (string, string) {
+			x := alpacadecimal.NewFromDecimal(decimal.RequireFromString(input)).String()
+			y := decimal.RequireFromString(input).String()
+			return x, y
+		})
+
+		require.True(t, alpacadecimal.NewFromDecimal(decimal.RequireFromString("1.23")).IsOptimized())
+		require.False(t, alpacadecimal.NewFromDecimal(decimal.RequireFromString("123456789")).IsOptimized())
+	})
+
 	t.Run("NewFromFloat", func(t *testing.T) {
 		x := alpacadecimal.NewFromFloat(1.234567)
 		y, err := alpacadecimal.NewFromString("1.234567")
@@ -168,6 +463,42 @@ func TestDecimal(t *testing.T) {
 		shouldEqual(t, x, y)
 	})
 
+	t.Run("NewFromFloatExact", func(t *testing.T) {
+		{
+			x, err := alpacadecimal.NewFromFloatExact(1.234567)
+			require.NoError(t, err)
+			require.True(t, x.IsOptimized())
+			shouldEqual(t, x, alpacadecimal.RequireFromString("1.234567"))
+		}
+
+		{
+			x, err := alpacadecimal.NewFromFloatExact(-0.1)
+			require.NoError(t, err)
+			require.True(t, x.IsOptimized())
+			shouldEqual(t, x, alpacadecimal.RequireFromString("-0.1"))
+		}
+
+		for _, f := range []float64{0.1234567890123, 1e7, -1e7, math.NaN(), math.Inf(1), math.Inf(-1)} {
+			_, err := alpacadecimal.NewFromFloatExact(f)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("NewFromFloatSafe", func(t *testing.T) {
+		for _, f := range []float64{0, 1.234567, -0.1, 0.1234567890123, 1e7, -1e20, math.SmallestNonzeroFloat64, math.MaxFloat64} {
+			x, err := alpacadecimal.NewFromFloatSafe(f)
+			require.NoError(t, err)
+			shouldEqual(t, x, alpacadecimal.NewFromFloat(f))
+			require.Equal(t, alpacadecimal.NewFromFloat(f).IsOptimized(), x.IsOptimized())
+		}
+
+		for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			_, err := alpacadecimal.NewFromFloatSafe(f)
+			require.Error(t, err)
+			require.Panics(t, func() { alpacadecimal.NewFromFloat(f) })
+		}
+	})
+
 	t.Run("NewFromFloat32", func(t *testing.T) {
 		x := alpacadecimal.NewFromFloat32(-1.23)
 		y, err := alpacadecimal.NewFromString("-1.23")
@@ -205,6 +536,16 @@ func TestDecimal(t *testing.T) {
 		shouldEqual(t, x, y)
 	})
 
+	t.Run("NewFromIntExact", func(t *testing.T) {
+		x, err := alpacadecimal.NewFromIntExact(-9_223_372)
+		require.NoError(t, err)
+		require.True(t, x.IsOptimized())
+		require.Equal(t, "-9223372", x.String())
+
+		_, err = alpacadecimal.NewFromIntExact(9_223_373)
+		require.ErrorIs(t, err, alpacadecimal.ErrOutOfRange)
+	})
+
 	t.Run("NewFromInt32", func(t *testing.T) {
 		x := alpacadecimal.NewFromInt32(-123)
 		y, err := alpacadecimal.NewFromString("-123")
@@ -212,6 +553,43 @@ func TestDecimal(t *testing.T) {
 		shouldEqual(t, x, y)
 	})
 
+	t.Run("NewFromJSONNumber", func(t *testing.T) {
+		var m map[string]interface{}
+		dec := json.NewDecoder(strings.NewReader(`{"int": 123, "neg": -45.67, "exp": 1.5e3, "tiny": 0.0000000000001, "large": 123456789012345678901234567890}`))
+		dec.UseNumber()
+		require.NoError(t, dec.Decode(&m))
+
+		for key, expected := range map[string]string{
+			"int":   "123",
+			"neg":   "-45.67",
+			"exp":   "1500",
+			"tiny":  "0.0000000000001",
+			"large": "123456789012345678901234567890",
+		} {
+			n := m[key].(json.Number)
+
+			x, err := alpacadecimal.NewFromJSONNumber(n)
+			require.NoError(t, err)
+			require.Equal(t, expected, x.String())
+			require.Equal(t, alpacadecimal.RequireFromString(expected).IsOptimized(), x.IsOptimized(), key)
+
+			var y alpacadecimal.Decimal
+			require.NoError(t, y.Scan(m[key]))
+			require.Equal(t, expected, y.String())
+
+			var z alpacadecimal.NullDecimal
+			require.NoError(t, z.Scan(m[key]))
+			require.True(t, z.Valid)
+			require.Equal(t, expected, z.Decimal.String())
+		}
+
+		_, err := alpacadecimal.NewFromJSONNumber(json.Number("abc"))
+		require.Error(t, err)
+
+		var d alpacadecimal.Decimal
+		require.Error(t, d.Scan(json.Number("")))
+	})
+
 	t.Run("NewFromString", func(t *testing.T) {
 		{
 			d, err := alpacadecimal.NewFromString("2")
@@ -270,6 +648,172 @@ func TestDecimal(t *testing.T) {
 		}
 	})
 
+	t.Run("NewFromString with tricky inputs", func(t *testing.T) {
+		tests := []struct {
+			input     string
+			valid     bool
+			optimized bool
+		}{
+			{"007", true, true},
+			{"-007.50", true, true},
+			{"+.5", true, true},
+			{"-.5", true, true},
+			{".5", true, true},
+			{"-.0", true, true},
+			{"+0", true, true},
+			{"0.50", true, true},
+			{"00.50", true, true},
+			{".000", true, true},
+			{"1.", true, true},
+			{"-1.", true, true},
+			{"0.000000000001", true, true},
+			{"0.0000000000010", true, true},
+			{"9223371.999999999999", true, true},
+			{"0.0000000000001", true, false},
+			{"9223372", true, false},
+			{"1e3", true, true},
+			{"1.23E+4", true, true},
+			{"-1.23e-2", true, true},
+			{"1.e1", true, true},
+			{".5E1", true, true},
+			{"1500e-14", true, true},
+			{"0e100", true, true},
+			{"1e-13", true, false},
+			{"1e7", true, false},
+			{"1e2147483648", false, false},
+			{"1e", false, false},
+			{"1e+", false, false},
+			{"e1", false, false},
+			{".e1", false, false},
+			{"1e2e3", false, false},
+			{"1e1.5", false, false},
+			{"", false, false},
+			{".", false, false},
+			{"-", false, false},
+			{"+", false, false},
+			{"-.", false, false},
+			{"+.", false, false},
+			{"1..", false, false},
+			{"1.2.3", false, false},
+			{"..5", false, false},
+			{"--1", false, false},
+			{"+-1", false, false},
+			{"1-", false, false},
+			{"1.-5", false, false},
+			{" 1", false, false},
+			{"1 ", false, false},
+			{"\"1\"", false, false},
+		}
+
+		for _, test := range tests {
+			x, err := alpacadecimal.NewFromString(test.input)
+			y, err2 := decimal.NewFromString(test.input)
+
+			if !test.valid {
+				require.Error(t, err, test.input)
+				require.Error(t, err2, test.input)
+				continue
+			}
+
+			require.NoError(t, err, test.input)
+			require.NoError(t, err2, test.input)
+			require.Equal(t, test.optimized, x.IsOptimized(), test.input)
+			require.Equal(t, y.String(), x.String(), test.input)
+		}
+	})
+
+	t.Run("NewFromStringCached", func(t *testing.T) {
+		defer alpacadecimal.ClearParseCache()
+
+		// twice, so the second round is served from cache
+		for i := 0; i < 2; i++ {
+			requireCompatible(t, func(input string) (string, string) {
+				x, err := alpacadecimal.NewFromStringCached(input)
+				require.NoError(t, err)
+				y := decimal.RequireFromString(input)
+				return x.String(), y.String()
+			})
+		}
+
+		x, err := alpacadecimal.NewFromStringCached("1.23")
+		require.NoError(t, err)
+		require.True(t, x.IsOptimized())
+		shouldEqual(t, alpacadecimal.RequireFromString("1.23"), x)
+
+		for i := 0; i < 2; i++ {
+			_, err = alpacadecimal.NewFromStringCached("error")
+			require.Error(t, err)
+		}
+
+		// beyond cache size is still parsed correctly
+		for i := 0; i < 5000; i++ {
+			x, err := alpacadecimal.NewFromStringCached(fmt.Sprintf("%d.5", i))
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprintf("%d.5", i), x.String())
+		}
+
+		alpacadecimal.ClearParseCache()
+		x, err = alpacadecimal.NewFromStringCached("1.23")
+		require.NoError(t, err)
+		require.Equal(t, "1.23", x.String())
+	})
+
+	t.Run("ParseAll", func(t *testing.T) {
+		{
+			ds, errs := alpacadecimal.ParseAll(cases)
+			require.Len(t, errs, len(cases))
+			require.Len(t, ds, len(cases))
+			for i, c := range cases {
+				require.NoError(t, errs[i])
+				require.Equal(t, decimal.RequireFromString(c).String(), ds[i].String())
+			}
+		}
+
+		{
+			ds, errs := alpacadecimal.ParseAll([]string{"1.5", "error", "123456789.1", ""})
+			require.Len(t, ds, 4)
+			require.Len(t, errs, 4)
+
+			require.NoError(t, errs[0])
+			shouldEqual(t, alpacadecimal.RequireFromString("1.5"), ds[0])
+			require.True(t, ds[0].IsOptimized())
+
+			require.Error(t, errs[1])
+			shouldEqual(t, alpacadecimal.Zero, ds[1])
+
+			require.NoError(t, errs[2])
+			shouldEqual(t, alpacadecimal.RequireFromString("123456789.1"), ds[2])
+			require.False(t, ds[2].IsOptimized())
+
+			require.Error(t, errs[3])
+		}
+
+		{
+			ds, errs := alpacadecimal.ParseAll(nil)
+			require.Empty(t, errs)
+			require.Empty(t, ds)
+		}
+	})
+
+	t.Run("ParseAllStopOnError", func(t *testing.T) {
+		ds, err := alpacadecimal.ParseAllStopOnError(cases)
+		require.NoError(t, err)
+		require.Len(t, ds, len(cases))
+		for i, c := range cases {
+			require.Equal(t, decimal.RequireFromString(c).String(), ds[i].String())
+		}
+
+		ds, err = alpacadecimal.ParseAllStopOnError([]string{"1.5", "123456789.1", "error", "2"})
+		require.EqualError(t, err, "can't parse value 2: can't convert error to decimal: exponent is not numeric")
+		require.Len(t, ds, 2)
+		shouldEqual(t, alpacadecimal.RequireFromString("1.5"), ds[0])
+		shouldEqual(t, alpacadecimal.RequireFromString("123456789.1"), ds[1])
+
+		ds, err = alpacadecimal.ParseAllStopOnError(nil)
+		require.NoError(t, err)
+		require.Empty(t, ds)
+	})
+
 	t.Run("RequireFromString", func(t *testing.T) {
 		x := alpacadecimal.RequireFromString("1")
 		shouldEqual(t, x, one)
@@ -279,14 +823,642 @@ func TestDecimal(t *testing.T) {
 		require.True(t, alpacadecimal.Sum(one, two).Equal(three))
 	})
 
+	t.Run("KahanSum", func(t *testing.T) {
+		require.True(t, alpacadecimal.KahanSum(nil).Equal(alpacadecimal.Zero))
+		require.True(t, alpacadecimal.KahanSum([]alpacadecimal.Decimal{one, two}).Equal(three))
+
+		// intermediate sums out of optimized range make naive Sum fallback until the sum is back in range
+		large := alpacadecimal.NewFromInt(9_000_000)
+		s := []alpacadecimal.Decimal{
+			large, large, large, large.Neg(), large.Neg(), large.Neg(),
+			alpacadecimal.RequireFromString("0.000000000001"),
+		}
+		require.False(t, alpacadecimal.Sum(s[0], s[1:4]...).IsOptimized())
+		naive := alpacadecimal.Sum(s[0], s[1:]...)
+		x := alpacadecimal.KahanSum(s)
+		require.True(t, x.IsOptimized())
+		require.Equal(t, "0.000000000001", x.String())
+		require.True(t, x.Equal(naive))
+
+		// far beyond int64 fixed range
+		ds := make([]alpacadecimal.Decimal, 0, 4000)
+		for i := 0; i < 2000; i++ {
+			ds = append(ds, large)
+		}
+		require.Equal(t, "18000000000", alpacadecimal.KahanSum(ds).String())
+		for i := 0; i < 2000; i++ {
+			ds = append(ds, large.Neg())
+		}
+		require.Equal(t, "0", alpacadecimal.KahanSum(ds).String())
+		require.Equal(t, "-18000000000", alpacadecimal.KahanSum(ds[2000:]).String())
+
+		// mixed fallback values
+		y := alpacadecimal.KahanSum([]alpacadecimal.Decimal{
+			alpacadecimal.RequireFromString("0.0000000000001"),
+			large,
+			alpacadecimal.RequireFromString("123456789.5"),
+			alpacadecimal.RequireFromString("-0.0000000000001"),
+			alpacadecimal.RequireFromString("-123456789"),
+		})
+		require.True(t, y.IsOptimized())
+		require.Equal(t, "9000000.5", y.String())
+
+		// fallback values with different exponents are accumulated exactly, with a couple of allocations per value at most
+		fs := make([]alpacadecimal.Decimal, 0, 300)
+		expected := decimal.Zero
+		for i := 0; i < 100; i++ {
+			for _, v := range []string{"0.0000000000001", "-12345678901234567890.5", "1e30"} {
+				fs = append(fs, alpacadecimal.RequireFromString(v))
+				expected = expected.Add(decimal.RequireFromString(v))
+			}
+		}
+		require.True(t, expected.Equal(*alpacadecimal.KahanSum(fs).GetFallback()))
+		allocs := testing.AllocsPerRun(10, func() { _ = alpacadecimal.KahanSum(fs) })
+		require.LessOrEqual(t, allocs, float64(2*len(fs)))
+
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			x := alpacadecimal.KahanSum([]alpacadecimal.Decimal{
+				alpacadecimal.RequireFromString(input1),
+				alpacadecimal.RequireFromString(input2),
+				alpacadecimal.RequireFromString(input1),
+			})
+			y := decimal.Sum(decimal.RequireFromString(input1), decimal.RequireFromString(input2), decimal.RequireFromString(input1))
+			return x.String(), y.String()
+		})
+	})
+
+	t.Run("ToFloat64Slice", func(t *testing.T) {
+		require.Equal(t, []float64{}, alpacadecimal.ToFloat64Slice(nil))
+
+		ds := make([]alpacadecimal.Decimal, 0, len(cases))
+		for _, c := range cases {
+			ds = append(ds, alpacadecimal.RequireFromString(c))
+		}
+
+		fs := alpacadecimal.ToFloat64Slice(ds)
+		require.Len(t, fs, len(ds))
+		for i, d := range ds {
+			expected := d.InexactFloat64()
+			if d.Abs().LessThanOrEqual(alpacadecimal.NewFromInt(9007)) || !d.IsOptimized() {
+				require.Equal(t, expected, fs[i], d.String())
+			} else {
+				require.InEpsilon(t, expected, fs[i], 1e-15, d.String())
+			}
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = alpacadecimal.ToFloat64Slice(ds[:10])
+		})
+		require.Equal(t, float64(1), allocs)
+	})
+
+	t.Run("WeightedAvg", func(t *testing.T) {
+		parse := func(inputs ...string) []alpacadecimal.Decimal {
+			ds := make([]alpacadecimal.Decimal, len(inputs))
+			for i, input := range inputs {
+				ds[i] = alpacadecimal.RequireFromString(input)
+			}
+			return ds
+		}
+
+		for _, c := range []struct {
+			values   []string
+			weights  []string
+			expected string
+		}{
+			// fills of an order, price weighted by qty
+			{[]string{"187.25", "187.30", "187.12"}, []string{"100", "250", "50"}, "187.265"},
+			// fractional shares
+			{[]string{"412.8831", "413.01"}, []string{"0.125", "2.5"}, "413.0039571428571429"},
+			// crypto, small prices and large quantities
+			{[]string{"0.00001234", "0.00001301"}, []string{"150000000", "2500000000"}, "0.0000129720754717"},
+			// short and long positions
+			{[]string{"50", "60"}, []string{"-100", "300"}, "65"},
+			// products out of the optimized range
+			{[]string{"5000000", "5000001"}, []string{"3000000", "1000000"}, "5000000.25"},
+			{[]string{"12345678901234567890"}, []string{"2"}, "12345678901234567890"},
+		} {
+			values, weights := parse(c.values...), parse(c.weights...)
+
+			x, err := alpacadecimal.WeightedAvg(values, weights)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, x.String(), c)
+
+			sum, total := decimal.Zero, decimal.Zero
+			for i := range c.values {
+				w := decimal.RequireFromString(c.weights[i])
+				sum = sum.Add(decimal.RequireFromString(c.values[i]).Mul(w))
+				total = total.Add(w)
+			}
+			require.Equal(t, sum.Div(total).String(), x.String(), c)
+		}
+
+		_, err := alpacadecimal.WeightedAvg(parse("1", "2"), parse("1"))
+		require.Error(t, err)
+		_, err = alpacadecimal.WeightedAvg(parse("1", "2"), parse("1", "-1"))
+		require.Error(t, err)
+		_, err = alpacadecimal.WeightedAvg(nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("Builder", func(t *testing.T) {
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			x1 := alpacadecimal.RequireFromString(input1)
+			x2 := alpacadecimal.RequireFromString(input2)
+
+			b := alpacadecimal.NewBuilder(x1)
+			x := b.Mul(x2).Add(x1).Sub(x2).Decimal()
+
+			expected := x1.Mul(x2).Add(x1).Sub(x2)
+			require.Equal(t, expected.IsOptimized(), x.IsOptimized())
+
+			if !x2.IsZero() {
+				x = b.Div(x2).Decimal()
+				expected = expected.Div(x2)
+				require.Equal(t, expected.IsOptimized(), x.IsOptimized())
+			}
+			return x.String(), expected.String()
+		})
+
+		// each op, from optimized and fallback current values, is the same as the method chain,
+		// including representation, coefficient and exponent
+		values := []alpacadecimal.Decimal{
+			one, two, three, alpacadecimal.NewFromInt(-7), alpacadecimal.RequireFromString("0.000000000001"),
+			alpacadecimal.NewFromInt(9000000), alpacadecimal.RequireFromString("12345678901234567890"),
+			alpacadecimal.RequireFromString("-0.0000000000001"), alpacadecimal.RequireFromString("1e20"),
+			alpacadecimal.NewFromDecimal(decimal.New(12, 3)), alpacadecimal.RequireFromString("-9223372.000000000001"),
+		}
+		ops := []struct {
+			name    string
+			builder func(b *alpacadecimal.Builder, d alpacadecimal.Decimal) *alpacadecimal.Builder
+			chain   func(x, d alpacadecimal.Decimal) alpacadecimal.Decimal
+		}{
+			{"Add", (*alpacadecimal.Builder).Add, alpacadecimal.Decimal.Add},
+			{"Sub", (*alpacadecimal.Builder).Sub, alpacadecimal.Decimal.Sub},
+			{"Mul", (*alpacadecimal.Builder).Mul, alpacadecimal.Decimal.Mul},
+			{"Div", (*alpacadecimal.Builder).Div, alpacadecimal.Decimal.Div},
+		}
+		for _, op := range ops {
+			for _, x := range values {
+				for _, d := range values {
+					for _, d2 := range values {
+						b := alpacadecimal.NewBuilder(x)
+						actual := op.builder(op.builder(&b, d), d2).Decimal()
+						expected := op.chain(op.chain(x, d), d2)
+
+						msg := fmt.Sprintf("%s %s, %s, %s", op.name, x, d, d2)
+						require.Equal(t, expected.IsOptimized(), actual.IsOptimized(), msg)
+						require.Equal(t, expected.Coefficient().String(), actual.Coefficient().String(), msg)
+						require.Equal(t, expected.Exponent(), actual.Exponent(), msg)
+					}
+				}
+			}
+		}
+
+		// stays optimized until an operation falls back
+		b := alpacadecimal.NewBuilder(one)
+		require.True(t, b.Add(two).Decimal().IsOptimized())
+		x := b.Mul(alpacadecimal.NewFromInt(10_000_000)).Decimal()
+		require.False(t, x.IsOptimized())
+		require.Equal(t, "30000000", x.String())
+		require.Equal(t, "10000000", b.Div(three).Decimal().String())
+	})
+
+	t.Run("Decoder", func(t *testing.T) {
+		dec := alpacadecimal.NewDecoder(strings.NewReader(strings.Join(cases, "\n") + "\n"))
+		for _, c := range cases {
+			x, err := dec.Decode()
+			require.NoError(t, err)
+
+			expected := alpacadecimal.RequireFromString(c)
+			require.True(t, expected.Equal(x), c)
+			require.Equal(t, expected.IsOptimized(), x.IsOptimized(), c)
+		}
+		_, err := dec.Decode()
+		require.Equal(t, io.EOF, err)
+
+		dec = alpacadecimal.NewDecoder(strings.NewReader(" 1.5\t\"-2\"\r\n\n  123456789.123456789 error 3"))
+		for _, expected := range []string{"1.5", "-2", "123456789.123456789"} {
+			x, err := dec.Decode()
+			require.NoError(t, err)
+			require.Equal(t, expected, x.String())
+		}
+		_, err = dec.Decode()
+		require.Error(t, err)
+		x, err := dec.Decode()
+		require.NoError(t, err)
+		require.Equal(t, "3", x.String())
+		_, err = dec.Decode()
+		require.Equal(t, io.EOF, err)
+
+		// buffer is reused
+		dec = alpacadecimal.NewDecoder(strings.NewReader(strings.Repeat("1234.5678\n", 1000)))
+		_, err = dec.Decode()
+		require.NoError(t, err)
+		allocs := testing.AllocsPerRun(100, func() {
+			x, err = dec.Decode()
+		})
+		require.NoError(t, err)
+		require.Equal(t, "1234.5678", x.String())
+		require.Equal(t, float64(0), allocs)
+	})
+
+	t.Run("OnFallback", func(t *testing.T) {
+		var ops []string
+		alpacadecimal.OnFallback = func(op string) { ops = append(ops, op) }
+		defer func() { alpacadecimal.OnFallback = nil }()
+
+		check := func(expected []string, f func()) {
+			ops = nil
+			f()
+			require.Equal(t, expected, ops)
+		}
+
+		large := alpacadecimal.RequireFromString("12345678901234567890")
+
+		// optimized
+		check(nil, func() {
+			x := alpacadecimal.New(123, -2).Add(alpacadecimal.NewFromInt(1)).Sub(alpacadecimal.NewFromFloat(1.5)).Mul(two).Div(two)
+			_ = x
+			_, _ = alpacadecimal.NewFromString("1.23")
+
+			var d alpacadecimal.Decimal
+			_ = d.Scan("1.23")
+			_ = d.UnmarshalJSON([]byte(`"1.23"`))
+			_ = d.UnmarshalText([]byte("1.23"))
+		})
+
+		// fallback
+		check([]string{"New"}, func() { alpacadecimal.New(1, 20) })
+		check([]string{"NewFromInt"}, func() { alpacadecimal.NewFromInt(math.MaxInt64) })
+		check([]string{"NewFromFloat"}, func() { alpacadecimal.NewFromFloat(1e20) })
+		check([]string{"NewFromString"}, func() { _, _ = alpacadecimal.NewFromString("12345678901234567890") })
+		check([]string{"Add"}, func() { large.Add(one) })
+		check([]string{"Sub"}, func() { one.Sub(large) })
+		check([]string{"Mul"}, func() { alpacadecimal.NewFromInt(9000000).Mul(two) })
+		check([]string{"Div"}, func() { one.Div(three) })
+		check([]string{"Scan", "NewFromFloat"}, func() {
+			var d alpacadecimal.Decimal
+			_ = d.Scan("12345678901234567890")
+			_ = d.Scan(float64(1e20))
+		})
+		check([]string{"UnmarshalJSON", "UnmarshalText"}, func() {
+			var d alpacadecimal.Decimal
+			_ = d.UnmarshalJSON([]byte(`"0.0000000000001"`))
+			_ = d.UnmarshalText([]byte("0.0000000000001"))
+		})
+		check([]string{"NewFromFloat32", "NewFromBigInt"}, func() {
+			alpacadecimal.NewFromFloat32(1.5)
+			alpacadecimal.NewFromBigInt(big.NewInt(1), 0)
+		})
+		check([]string{"Parse", "ParseAll", "ParsePrefix", "Decode"}, func() {
+			var d alpacadecimal.Decimal
+			_ = d.Parse([]byte("0.0000000000001"))
+			_, _ = alpacadecimal.ParseAll([]string{"1", "0.0000000000001"})
+			_, _, _ = alpacadecimal.ParsePrefix([]byte("0.0000000000001;"))
+			_, _ = alpacadecimal.NewDecoder(strings.NewReader("1 0.0000000000001")).Decode()
+			_, _ = alpacadecimal.NewDecoder(strings.NewReader("0.0000000000001")).Decode()
+		})
+		check([]string{"MulInt", "MulPow10", "MulPow10", "MulRound", "FMA"}, func() {
+			alpacadecimal.NewFromInt(9000000).MulInt(2)
+			one.MulPow10(7)
+			one.DivPow10(13)
+			alpacadecimal.NewFromInt(9000000).MulRound(two, 2)
+			alpacadecimal.NewFromInt(9000000).FMA(two, one)
+		})
+		check([]string{"Div"}, func() {
+			_, exact := one.DivInt(3)
+			require.False(t, exact)
+		})
+		check([]string{"Neg", "Abs", "RoundWithMode", "RoundWithMode", "RoundWithMode"}, func() {
+			large.Neg()
+			large.Abs()
+			large.Round(2)
+			large.RoundBank(2)
+			large.RoundWithMode(2, alpacadecimal.RoundUp)
+		})
+		check([]string{"Pow10", "AbsDiff", "NewFromDecimal", "KahanSum"}, func() {
+			alpacadecimal.Pow10(20)
+			alpacadecimal.NewFromInt(-9000000).AbsDiff(alpacadecimal.NewFromInt(9000000))
+			alpacadecimal.NewFromDecimal(decimal.New(1, 20))
+			alpacadecimal.KahanSum([]alpacadecimal.Decimal{large, one})
+		})
+		check([]string{"DivMod", "DivRoundBank", "DivToScale", "SplitN", "UnmarshalBinary"}, func() {
+			large.DivMod(two)
+			large.DivRoundBank(three, 2)
+			_, _ = large.DivToScale(three, -2, alpacadecimal.RoundHalfEven)
+			_, _ = large.SplitN(3, 2)
+
+			b, err := large.MarshalBinary()
+			require.NoError(t, err)
+			var d alpacadecimal.Decimal
+			require.NoError(t, d.GobDecode(b))
+		})
+		check([]string{"Ceil", "Floor", "Truncate", "RoundCash", "Quantize"}, func() {
+			large.Ceil()
+			large.Floor()
+			large.Truncate(2)
+			large.RoundCash(5)
+			_, _ = one.Quantize(-14, alpacadecimal.RoundHalfEven)
+		})
+		check([]string{"Div", "Div"}, func() {
+			three.Reciprocal()
+			_, exact := three.ReciprocalExact()
+			require.False(t, exact)
+		})
+		check(nil, func() {
+			alpacadecimal.Pow10(-2)
+			one.AbsDiff(three)
+			alpacadecimal.NewFromDecimal(decimal.New(1, 2))
+			alpacadecimal.KahanSum([]alpacadecimal.Decimal{one, two})
+			one.DivMod(two)
+			one.DivRoundBank(three, 2)
+			_, _ = one.DivToScale(three, -2, alpacadecimal.RoundHalfEven)
+			_, _ = three.SplitN(2, 2)
+			three.Ceil().Floor().Truncate(2).RoundCash(5)
+			_, _ = one.Quantize(-2, alpacadecimal.RoundHalfEven)
+			_, _ = two.ReciprocalExact()
+		})
+		check([]string{"Mul", "Add", "Sub", "Div"}, func() {
+			b := alpacadecimal.NewBuilder(alpacadecimal.NewFromInt(9000000))
+			b.Mul(two).Add(one).Sub(one).Div(two)
+		})
+		check(nil, func() {
+			b := alpacadecimal.NewBuilder(one)
+			b.Mul(two).Add(one).Sub(one).Div(two)
+			one.MulInt(2).MulPow10(2).DivPow10(2).MulRound(two, 2).FMA(two, one).Neg().Abs().Round(2)
+		})
+
+		// no overhead when not set
+		alpacadecimal.OnFallback = nil
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = one.Add(two).Sub(three).Mul(two).Div(two)
+		})
+		require.Equal(t, float64(0), allocs)
+	})
+
+	t.Run("Scanner", func(t *testing.T) {
+		var _ sql.Scanner = alpacadecimal.Scanner{}
+
+		for _, strict := range []bool{false, true} {
+			for _, c := range cases {
+				var d alpacadecimal.Decimal
+				err := alpacadecimal.Scanner{Decimal: &d, Strict: strict}.Scan(c)
+
+				expected := alpacadecimal.RequireFromString(c)
+				if strict && !expected.IsOptimized() {
+					require.ErrorIs(t, err, alpacadecimal.ErrOutOfRange, c)
+					require.True(t, d.IsZero())
+					continue
+				}
+				require.NoError(t, err, c)
+				require.Equal(t, expected.String(), d.String())
+				require.Equal(t, expected.IsOptimized(), d.IsOptimized(), c)
+			}
+		}
+
+		for _, value := range []interface{}{"1.5", []byte("1.5"), float64(1.5), float32(1.5), int64(3), "1.500000000000000000"} {
+			var d alpacadecimal.Decimal
+			require.NoError(t, alpacadecimal.Scanner{Decimal: &d, Strict: true}.Scan(value), value)
+			require.True(t, d.IsOptimized())
+		}
+
+		for _, value := range []interface{}{"123456789.5", "0.0000000000001", float32(0.1), float64(1e20), int64(123456789)} {
+			d := alpacadecimal.NewFromInt(7)
+			require.Error(t, alpacadecimal.Scanner{Decimal: &d, Strict: true}.Scan(value), value)
+			require.Equal(t, "7", d.String())
+
+			require.NoError(t, alpacadecimal.Scanner{Decimal: &d}.Scan(value), value)
+			require.False(t, d.IsOptimized())
+		}
+
+		// errors of Scan itself
+		for _, strict := range []bool{false, true} {
+			var d alpacadecimal.Decimal
+			require.Error(t, alpacadecimal.Scanner{Decimal: &d, Strict: strict}.Scan(nil))
+			require.Error(t, alpacadecimal.Scanner{Decimal: &d, Strict: strict}.Scan("error"))
+		}
+	})
+
+	t.Run("Scanner with separators", func(t *testing.T) {
+		scan := func(s alpacadecimal.Scanner, value interface{}) (string, error) {
+			var d alpacadecimal.Decimal
+			s.Decimal = &d
+			err := s.Scan(value)
+			return d.String(), err
+		}
+
+		comma := alpacadecimal.Scanner{DecimalSeparator: ','}
+		for _, c := range []struct {
+			scanner  alpacadecimal.Scanner
+			value    interface{}
+			expected string
+		}{
+			{comma, "1,23", "1.23"},
+			{comma, []byte("-1,23"), "-1.23"},
+			{comma, sql.RawBytes(" 0,5 "), "0.5"},
+			{comma, "123", "123"},
+			{comma, "1,5e3", "1500"},
+			{comma, int64(7), "7"},
+			{comma, float64(1.25), "1.25"},
+			{comma, "12345678901234567890,5", "12345678901234567890.5"},
+			{alpacadecimal.Scanner{DecimalSeparator: ',', GroupSeparator: '.'}, "1.234.567,89", "1234567.89"},
+			{alpacadecimal.Scanner{DecimalSeparator: ',', GroupSeparator: ' '}, "1 234,5", "1234.5"},
+			{alpacadecimal.Scanner{GroupSeparator: ','}, "1,234,567.89", "1234567.89"},
+			{alpacadecimal.Scanner{DecimalSeparator: '.', GroupSeparator: '\''}, "1'234.5", "1234.5"},
+		} {
+			x, err := scan(c.scanner, c.value)
+			require.NoError(t, err, c.value)
+			require.Equal(t, c.expected, x, c.value)
+		}
+
+		// default is strictly '.'
+		_, err := scan(alpacadecimal.Scanner{}, "1,23")
+		require.Error(t, err)
+
+		for _, c := range []struct {
+			scanner alpacadecimal.Scanner
+			value   interface{}
+		}{
+			{comma, "1.23"},
+			{comma, "1.234,5"},
+			{comma, "1,2,3"},
+			{alpacadecimal.Scanner{DecimalSeparator: ',', GroupSeparator: ','}, "1,23"},
+			{alpacadecimal.Scanner{GroupSeparator: '.'}, "1.23"},
+		} {
+			d := alpacadecimal.NewFromInt(7)
+			s := c.scanner
+			s.Decimal = &d
+			require.Error(t, s.Scan(c.value), c.value)
+			require.Equal(t, "7", d.String())
+		}
+
+		// with strict mode
+		_, err = scan(alpacadecimal.Scanner{DecimalSeparator: ',', Strict: true}, "12345678901234567890,5")
+		require.ErrorIs(t, err, alpacadecimal.ErrOutOfRange)
+	})
+
 	t.Run("Decimal.Abs", func(t *testing.T) {
 		require.True(t, alpacadecimal.NewFromInt(-1).Abs().Equal(one))
 	})
 
+	t.Run("Decimal.AbsDiff", func(t *testing.T) {
+		check := func(a, b string, expected string, optimized bool) {
+			x := alpacadecimal.RequireFromString(a).AbsDiff(alpacadecimal.RequireFromString(b))
+			require.Equal(t, expected, x.String())
+			require.Equal(t, optimized, x.IsOptimized(), "|%s - %s|", a, b)
+
+			y := alpacadecimal.RequireFromString(b).AbsDiff(alpacadecimal.RequireFromString(a))
+			require.Equal(t, expected, y.String())
+		}
+
+		check("1.5", "1.25", "0.25", true)
+		check("0", "0", "0", true)
+
+		// crossing zero
+		check("-1.5", "1.25", "2.75", true)
+		check("-0.000000000001", "0.000000000001", "0.000000000002", true)
+		check("-4611686", "4611686", "9223372", true)
+		check("-4611686", "4611686.000000000001", "9223372.000000000001", false)
+		check("-9223371.999999999999", "9223371.999999999999", "18446743.999999999998", false)
+		check("-123456789.5", "0.5", "123456790", false)
+
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			x := alpacadecimal.RequireFromString(input1).AbsDiff(alpacadecimal.RequireFromString(input2)).String()
+			y := decimal.RequireFromString(input1).Sub(decimal.RequireFromString(input2)).Abs().String()
+			return x, y
+		})
+
+		x := alpacadecimal.RequireFromString("-9223371.999999999999")
+		y := alpacadecimal.RequireFromString("0.000000000001")
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = x.AbsDiff(y)
+		})
+		require.Equal(t, float64(0), allocs)
+	})
+
 	t.Run("Decimal.Add", func(t *testing.T) {
 		require.True(t, one.Add(two).Equal(three))
 	})
 
+	t.Run("Decimal.Add & Sub of optimized and fallback", func(t *testing.T) {
+		check := func(x alpacadecimal.Decimal, expected string, optimized bool, exp int32) {
+			require.Equal(t, expected, x.String())
+			require.Equal(t, optimized, x.IsOptimized(), expected)
+			if !optimized {
+				require.Equal(t, exp, x.Exponent(), expected)
+			}
+		}
+
+		tiny := alpacadecimal.RequireFromString("0.0000000000001")
+		large := alpacadecimal.RequireFromString("12345678901234567890")
+		precise := alpacadecimal.RequireFromString("334.94378539458934589345")
+
+		// canonical, without the trailing zeros of exponent -12
+		check(one.Add(tiny), "1.0000000000001", false, -13)
+		check(tiny.Sub(one), "-0.9999999999999", false, -13)
+		check(one.Add(large), "12345678901234567891", false, 0)
+		check(large.Sub(alpacadecimal.RequireFromString("0.5")), "12345678901234567889.5", false, -1)
+		check(alpacadecimal.NewFromInt(9_000_000).Add(alpacadecimal.NewFromInt(9_000_000)), "18000000", false, 6)
+
+		// optimized again when representable
+		check(tiny.Sub(tiny), "0", true, 0)
+		check(tiny.Neg().Add(tiny), "0", true, 0)
+		check(large.Sub(large).Add(one), "1", true, 0)
+		check(one.Mul(precise).Add(one).Sub(precise), "1", true, 0)
+		check(alpacadecimal.RequireFromString("0.50000000000000000001").Sub(alpacadecimal.RequireFromString("0.00000000000000000001")), "0.5", true, 0)
+		check(large.Add(large.Neg().Add(tiny.Add(tiny))), "0.0000000000002", false, -13)
+
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			a, b := alpacadecimal.RequireFromString(input1), alpacadecimal.RequireFromString(input2)
+			for _, x := range []alpacadecimal.Decimal{a.Add(b), a.Sub(b)} {
+				_, ok := x.EncodeFixed()
+				require.Equal(t, ok, x.IsOptimized(), x.String())
+			}
+
+			aa, bb := decimal.RequireFromString(input1), decimal.RequireFromString(input2)
+			return a.Add(b).String() + a.Sub(b).String(), aa.Add(bb).String() + aa.Sub(bb).String()
+		})
+	})
+
+	t.Run("Decimal.AddExact & SubExact & MulExact", func(t *testing.T) {
+		x, err := one.AddExact(two)
+		require.NoError(t, err)
+		require.True(t, x.IsOptimized())
+		require.True(t, x.Equal(three))
+
+		x, err = three.SubExact(two)
+		require.NoError(t, err)
+		require.True(t, x.Equal(one))
+
+		x, err = two.MulExact(three)
+		require.NoError(t, err)
+		require.Equal(t, "6", x.String())
+
+		// in range result of fallback inputs is converted back to optimized
+		large := alpacadecimal.NewFromInt(100_000_000)
+		x, err = large.SubExact(large.Sub(one))
+		require.NoError(t, err)
+		require.True(t, x.IsOptimized())
+		require.True(t, x.Equal(one))
+
+		max := alpacadecimal.NewFromInt(9_223_372)
+		_, err = max.AddExact(one)
+		require.ErrorIs(t, err, alpacadecimal.ErrOutOfRange)
+
+		_, err = max.Neg().SubExact(one)
+		require.ErrorIs(t, err, alpacadecimal.ErrOutOfRange)
+
+		_, err = max.MulExact(two)
+		require.ErrorIs(t, err, alpacadecimal.ErrOutOfRange)
+
+		// too much precision is out of range too
+		small := alpacadecimal.RequireFromString("0.0000001")
+		_, err = small.MulExact(small)
+		require.ErrorIs(t, err, alpacadecimal.ErrOutOfRange)
+	})
+
+	t.Run("Decimal.AppendFixed", func(t *testing.T) {
+		for places := int32(-2); places <= 14; places++ {
+			requireCompatible(t, func(input string) (string, string) {
+				x := alpacadecimal.RequireFromString(input).AppendFixed([]byte("x="), places)
+				y := decimal.RequireFromString(input).StringFixed(places)
+				return string(x), "x=" + y
+			})
+		}
+
+		require.Equal(t, "1.00", string(one.AppendFixed(nil, 2)))
+		require.Equal(t, "-0.01", string(alpacadecimal.RequireFromString("-0.005").AppendFixed(nil, 2)))
+		require.Equal(t, "0.00", string(alpacadecimal.RequireFromString("-0.004").AppendFixed(nil, 2)))
+		require.Equal(t, "9223372.00", string(alpacadecimal.RequireFromString("9223371.995").AppendFixed(nil, 2)))
+
+		x := alpacadecimal.RequireFromString("-1234.5678")
+		buf := make([]byte, 0, 64)
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = x.AppendFixed(buf[:0], 2)
+		})
+		require.Equal(t, float64(0), allocs)
+		require.Equal(t, "-1234.57", string(buf))
+	})
+
+	t.Run("Decimal.AppendText", func(t *testing.T) {
+		requireCompatible(t, func(input string) (string, string) {
+			x, err := alpacadecimal.RequireFromString(input).AppendText([]byte("x="))
+			require.NoError(t, err)
+			y, err := alpacadecimal.RequireFromString(input).MarshalText()
+			require.NoError(t, err)
+			return string(x), "x=" + string(y)
+		})
+
+		x := alpacadecimal.RequireFromString("-1234.5678")
+		buf := make([]byte, 0, 64)
+		allocs := testing.AllocsPerRun(100, func() {
+			buf, _ = x.AppendText(buf[:0])
+		})
+		require.Equal(t, float64(0), allocs)
+		require.Equal(t, "-1234.5678", string(buf))
+	})
+
 	t.Run("Decimal.Atan", func(t *testing.T) {
 		requireCompatible(t, func(input string) (string, string) {
 			x := alpacadecimal.RequireFromString(input).Atan().String()
@@ -311,6 +1483,86 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.Canonical", func(t *testing.T) {
+		requireCompatible(t, func(input string) (string, string) {
+			x := alpacadecimal.RequireFromString(input)
+			require.True(t, x.Equal(x.Canonical()))
+			return x.Canonical().String(), decimal.RequireFromString(input).String()
+		})
+
+		canonical := func(value int64, exp int32) alpacadecimal.Decimal {
+			return alpacadecimal.NewFromBigInt(big.NewInt(value), exp).Canonical()
+		}
+
+		// equal numbers with different representations
+		for _, group := range [][]alpacadecimal.Decimal{
+			{alpacadecimal.RequireFromString("1.5"), canonical(150, -2), canonical(15_000_000_000_000_000, -16)},
+			{alpacadecimal.Zero, canonical(0, -20), canonical(0, 5)},
+			{canonical(123456789_150, -3), canonical(1234567891500, -4), alpacadecimal.RequireFromString("123456789.15")},
+			{canonical(15, -14), canonical(1500, -16), alpacadecimal.RequireFromString("0.00000000000015")},
+			{canonical(-12, 10), canonical(-1200, 8), alpacadecimal.RequireFromString("-120000000000")},
+		} {
+			y := group[0].Canonical()
+			for _, x := range group[1:] {
+				x = x.Canonical()
+				require.True(t, x.Equal(y))
+				require.Equal(t, y.String(), x.String())
+				require.Equal(t, y.IsOptimized(), x.IsOptimized(), x.String())
+				require.Equal(t, y.Exponent(), x.Exponent(), x.String())
+				require.Equal(t, y.Coefficient(), x.Coefficient(), x.String())
+			}
+		}
+
+		x := canonical(123456789_150, -3)
+		require.False(t, x.IsOptimized())
+		require.Equal(t, int32(-2), x.Exponent())
+		require.Equal(t, "12345678915", x.Coefficient().String())
+	})
+
+	t.Run("Decimal.CanonicalCoefficient", func(t *testing.T) {
+		// decimal.Decimal keeps the exponent of the input, e.g. "1.50" is 150 * 10^-2
+		normalize := func(d decimal.Decimal) string {
+			if d.IsZero() {
+				return "0e0"
+			}
+			c, exp := d.Coefficient(), d.Exponent()
+			ten := big.NewInt(10)
+			for new(big.Int).Rem(c, ten).Sign() == 0 {
+				c.Quo(c, ten)
+				exp++
+			}
+			return fmt.Sprintf("%se%d", c, exp)
+		}
+
+		requireCompatible(t, func(input string) (string, string) {
+			c, exp := alpacadecimal.RequireFromString(input).CanonicalCoefficient()
+			return fmt.Sprintf("%se%d", c, exp), normalize(decimal.RequireFromString(input))
+		})
+
+		check := func(d alpacadecimal.Decimal, coefficient string, exp int32) {
+			c, e := d.CanonicalCoefficient()
+			require.Equal(t, coefficient, c.String(), d.String())
+			require.Equal(t, exp, e, d.String())
+		}
+
+		check(alpacadecimal.RequireFromString("1.50"), "15", -1)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(150), -2), "15", -1)
+		check(alpacadecimal.NewFromInt(123), "123", 0)
+		check(alpacadecimal.NewFromInt(-1200), "-12", 2)
+		check(alpacadecimal.Zero, "0", 0)
+		check(alpacadecimal.New(0, -20), "0", 0)
+		check(alpacadecimal.RequireFromString("-0.000000000001"), "-1", -12)
+		check(alpacadecimal.RequireFromString("0.00000000000015"), "15", -14)
+		check(alpacadecimal.RequireFromString("123456789.150"), "12345678915", -2)
+		check(alpacadecimal.New(12, 30), "12", 30)
+
+		// the coefficient is not shared
+		d := alpacadecimal.RequireFromString("123456789.150")
+		c, _ := d.CanonicalCoefficient()
+		c.SetInt64(0)
+		require.Equal(t, "123456789.15", d.String())
+	})
+
 	t.Run("Decimal.Ceil", func(t *testing.T) {
 		a1 := alpacadecimal.RequireFromString("1.234")
 		b1 := alpacadecimal.RequireFromString("2")
@@ -347,8 +1599,48 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.CmpDecimal & EqualDecimal", func(t *testing.T) {
+		requireCompatible2(t, func(input1, input2 string) (int, int) {
+			x := alpacadecimal.RequireFromString(input1).CmpDecimal(decimal.RequireFromString(input2))
+			y := decimal.RequireFromString(input1).Cmp(decimal.RequireFromString(input2))
+			return x, y
+		})
+
+		requireCompatible2(t, func(input1, input2 string) (bool, bool) {
+			x := alpacadecimal.RequireFromString(input1).EqualDecimal(decimal.RequireFromString(input2))
+			y := decimal.RequireFromString(input1).Equal(decimal.RequireFromString(input2))
+			return x, y
+		})
+
+		// d2 with various exponents
+		x := alpacadecimal.NewFromInt(1200)
+		for _, c := range []struct {
+			d2       decimal.Decimal
+			expected int
+		}{
+			{decimal.New(12, 2), 0},
+			{decimal.New(11, 2), 1},
+			{decimal.New(13, 2), -1},
+			{decimal.New(1200000, -3), 0},
+			{decimal.New(1200000000000000001, -15), -1},
+			{decimal.New(1199999999999999999, -15), 1},
+			{decimal.New(-12, 2), 1},
+			{decimal.New(1, 30), -1},
+			{decimal.New(1, -30), 1},
+		} {
+			require.Equal(t, c.expected, x.CmpDecimal(c.d2), c.d2.String())
+			require.Equal(t, c.expected == 0, x.EqualDecimal(c.d2), c.d2.String())
+			require.Equal(t, -c.expected, x.Neg().CmpDecimal(c.d2.Neg()), c.d2.String())
+		}
+
+		y := alpacadecimal.RequireFromString("1200.5")
+		require.Equal(t, 1, y.CmpDecimal(decimal.New(12, 2)))
+		require.Equal(t, -1, y.Neg().CmpDecimal(decimal.New(-12, 2)))
+		require.Equal(t, -1, y.CmpDecimal(decimal.New(13, 2)))
+	})
+
 	t.Run("Decimal.Coefficient", func(t *testing.T) {
-		// this is not fully compatible
+		// this is not fully compatible, see Decimal.CanonicalCoefficient
 		//
 		// requireCompatible(t, func(input string) (string, string) {
 		// 	x := alpacadecimal.RequireFromString(input).Coefficient().String()
@@ -367,6 +1659,30 @@ func TestDecimal(t *testing.T) {
 		// })
 	})
 
+	t.Run("Decimal.CoefficientText", func(t *testing.T) {
+		for _, base := range []int{2, 10, 16, 36} {
+			requireCompatible(t, func(input string) (string, string) {
+				d := alpacadecimal.RequireFromString(input)
+				coef, ok := new(big.Int).SetString(d.CoefficientText(base), base)
+				require.True(t, ok)
+				x := decimal.NewFromBigInt(coef, d.Exponent()).String()
+				y := decimal.RequireFromString(input).String()
+				return x, y
+			})
+		}
+
+		x := alpacadecimal.NewFromInt(1)
+		require.Equal(t, "e8d4a51000", x.CoefficientText(16))
+		require.Equal(t, int32(-12), x.Exponent())
+
+		y := alpacadecimal.NewFromInt(-123456789)
+		require.Equal(t, "-75bcd15", y.CoefficientText(16))
+		require.Equal(t, int32(0), y.Exponent())
+
+		require.Panics(t, func() { x.CoefficientText(1) })
+		require.Panics(t, func() { y.CoefficientText(37) })
+	})
+
 	t.Run("Decimal.Copy", func(t *testing.T) {
 		{
 			var a alpacadecimal.Decimal
@@ -416,11 +1732,227 @@ func TestDecimal(t *testing.T) {
 
 		checkFloatDiv(1.1, 2.2, "0.5")
 		checkFloatDiv(2.3, 0.3, "7.6666666666666667") // 16 precision
+
+		// terminating quotients stay exact and optimized
+		checkExactDiv := func(a, b string, expected string) {
+			x := alpacadecimal.RequireFromString(a).Div(alpacadecimal.RequireFromString(b))
+			require.Equal(t, expected, x.String())
+			require.True(t, x.IsOptimized(), "%s / %s", a, b)
+		}
+
+		checkExactDiv("1", "8", "0.125")
+		checkExactDiv("1", "16", "0.0625")
+		checkExactDiv("-3", "0.16", "-18.75")
+		checkExactDiv("6847245.80224622965", "2", "3423622.901123114825")
+		checkExactDiv("3106919.167034404512", "-32", "-97091.223969825141")
+		checkExactDiv("-3765511.354330880119", "7", "-537930.193475840017")
+
+		// out of range
+		x := alpacadecimal.RequireFromString("9223371.999999999999").Div(alpacadecimal.RequireFromString("0.5"))
+		require.Equal(t, "18446743.999999999998", x.String())
+		require.False(t, x.IsOptimized())
+
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			if decimal.RequireFromString(input2).IsZero() {
+				return "", ""
+			}
+			x := alpacadecimal.RequireFromString(input1).Div(alpacadecimal.RequireFromString(input2)).String()
+			y := decimal.RequireFromString(input1).Div(decimal.RequireFromString(input2)).String()
+			return x, y
+		})
+	})
+
+	t.Run("Decimal.DivInt", func(t *testing.T) {
+		check := func(input string, n int64, expected string, expectedExact bool) {
+			x, exact := alpacadecimal.RequireFromString(input).DivInt(n)
+			require.Equal(t, expected, x.String())
+			require.Equal(t, expectedExact, exact)
+		}
+
+		check("10", 4, "2.5", true)
+		check("-10", 4, "-2.5", true)
+		check("10", -4, "-2.5", true)
+		check("0.03", 3, "0.01", true)
+		check("0", 7, "0", true)
+		check("10", 3, "3.3333333333333333", false)
+		check("0.000000000001", 2, "0.0000000000005", true)
+		check("123456789", 3, "41152263", true)
+		check("1", 0, "0", false)
+
+		{
+			x, exact := alpacadecimal.RequireFromString("100.5").DivInt(2)
+			require.True(t, exact)
+			require.True(t, x.IsOptimized())
+		}
+
+		for _, n := range []int64{1, -1, 2, 3, -7, 1000} {
+			requireCompatible(t, func(input string) (string, string) {
+				x, _ := alpacadecimal.RequireFromString(input).DivInt(n)
+				y := decimal.RequireFromString(input).Div(decimal.NewFromInt(n))
+				return x.String(), y.String()
+			})
+		}
+	})
+
+	t.Run("Decimal.DivMod", func(t *testing.T) {
+		check := func(a, b string, expectedQ, expectedR string) {
+			q, r := alpacadecimal.RequireFromString(a).DivMod(alpacadecimal.RequireFromString(b))
+			require.Equal(t, expectedQ, q.String())
+			require.Equal(t, expectedR, r.String())
+		}
+
+		check("7", "2", "3", "1")
+		// truncated toward zero like Mod, unlike Python's divmod(-7, 2) == (-4, 1)
+		check("-7", "2", "-3", "-1")
+		check("7", "-2", "-3", "1")
+		check("-7", "-2", "3", "-1")
+		check("-12345678901234567897", "2", "-6172839450617283948", "-1")
+		check("-7.5", "2", "-3", "-1.5")
+		check("10.75", "0.25", "43", "0")
+		check("10.80", "0.25", "43", "0.05")
+		check("0.000000000003", "0.000000000002", "1", "0.000000000001")
+		check("9000000", "0.000000000001", "9000000000000000000", "0")
+		check("123456789.5", "2", "61728394", "1.5")
+
+		require.Panics(t, func() { one.DivMod(alpacadecimal.Zero) })
+
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			if alpacadecimal.RequireFromString(input2).IsZero() {
+				// skip if div by zero
+				return "", ""
+			}
+
+			q, r := alpacadecimal.RequireFromString(input1).DivMod(alpacadecimal.RequireFromString(input2))
+			x := q.String() + ":" + r.String()
+
+			// remainder is consistent with Mod
+			shouldEqual(t, r, alpacadecimal.RequireFromString(input1).Mod(alpacadecimal.RequireFromString(input2)))
+
+			qq, rr := decimal.RequireFromString(input1).QuoRem(decimal.RequireFromString(input2), 0)
+			y := qq.String() + ":" + rr.String()
+			return x, y
+		})
+	})
+
+	t.Run("Decimal.DivRound", func(t *testing.T) {
+		// 3/4 = 0.75 => round 1 position => 0.8
+		shouldEqual(t, three.DivRound(alpacadecimal.NewFromInt(4), 1), alpacadecimal.NewFromFloat(0.8))
 	})
 
-	t.Run("Decimal.DivRound", func(t *testing.T) {
-		// 3/4 = 0.75 => round 1 position => 0.8
-		shouldEqual(t, three.DivRound(alpacadecimal.NewFromInt(4), 1), alpacadecimal.NewFromFloat(0.8))
+	t.Run("Decimal.DivRoundBank", func(t *testing.T) {
+		// exact x / y rounded half to even, as oracle
+		divRoundBank := func(x, y decimal.Decimal, places int32) decimal.Decimal {
+			r := new(big.Rat).Quo(x.Rat(), y.Rat())
+			r.Mul(r, decimal.New(1, places).Rat())
+
+			q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+			c := new(big.Int).Lsh(m.Abs(m), 1).Cmp(r.Denom())
+			if c > 0 || (c == 0 && q.Bit(0) == 1) {
+				q.Add(q, big.NewInt(int64(r.Sign())))
+			}
+			return decimal.NewFromBigInt(q, -places)
+		}
+
+		for _, places := range []int32{-1, 0, 1, 2, 5, 12, 14} {
+			requireCompatible2(t, func(input1, input2 string) (string, string) {
+				if decimal.RequireFromString(input2).IsZero() {
+					return "", ""
+				}
+				x := alpacadecimal.RequireFromString(input1).DivRoundBank(alpacadecimal.RequireFromString(input2), places)
+				y := divRoundBank(decimal.RequireFromString(input1), decimal.RequireFromString(input2), places)
+				return x.String(), y.String()
+			})
+		}
+
+		check := func(x, y string, places int32, expected string) {
+			r := alpacadecimal.RequireFromString(x).DivRoundBank(alpacadecimal.RequireFromString(y), places)
+			require.Equal(t, expected, r.String(), "%s / %s", x, y)
+			require.True(t, r.IsOptimized())
+		}
+
+		check("1", "8", 2, "0.12")
+		check("3", "8", 2, "0.38")
+		check("-1", "8", 2, "-0.12")
+		check("1", "-8", 2, "-0.12")
+		check("-3", "8", 2, "-0.38")
+		check("5", "2", 0, "2")
+		check("7", "2", 0, "4")
+		check("1", "3", 2, "0.33")
+		check("2", "3", 2, "0.67")
+		check("0.025", "1", 2, "0.02")
+
+		// out of optimized range
+		r := alpacadecimal.NewFromInt(9_223_371).DivRoundBank(alpacadecimal.RequireFromString("0.5"), 0)
+		require.False(t, r.IsOptimized())
+		require.Equal(t, "18446742", r.String())
+
+		// d.Div(d2).RoundBank(places) rounds twice, 0.13499999999999999999 => 0.135 => 0.14
+		x := alpacadecimal.RequireFromString("0.13499999999999999999")
+		require.Equal(t, "0.14", x.Div(one).RoundBank(2).String())
+		require.Equal(t, "0.13", x.DivRoundBank(one, 2).String())
+	})
+
+	t.Run("Decimal.DivToScale", func(t *testing.T) {
+		modes := []alpacadecimal.RoundingMode{
+			alpacadecimal.RoundHalfAwayFromZero,
+			alpacadecimal.RoundHalfEven,
+			alpacadecimal.RoundHalfUp,
+			alpacadecimal.RoundCeil,
+			alpacadecimal.RoundFloor,
+			alpacadecimal.RoundDown,
+			alpacadecimal.RoundUp,
+		}
+
+		// same as Div then RoundWithMode, with enough precision to avoid double rounding
+		func() {
+			defer func(p int) { alpacadecimal.DivisionPrecision = p }(alpacadecimal.DivisionPrecision)
+			alpacadecimal.DivisionPrecision = 40
+
+			for _, mode := range modes {
+				for _, exp := range []int32{-14, -12, -4, -2, 0, 2} {
+					requireCompatible2(t, func(input1, input2 string) (string, string) {
+						d, d2 := alpacadecimal.RequireFromString(input1), alpacadecimal.RequireFromString(input2)
+						if d2.IsZero() {
+							return "", ""
+						}
+						x, err := d.DivToScale(d2, exp, mode)
+						require.NoError(t, err)
+						return x.String(), d.Div(d2).RoundWithMode(-exp, mode).String()
+					})
+				}
+			}
+		}()
+
+		check := func(x, y string, exp int32, mode alpacadecimal.RoundingMode, expected string) {
+			r, err := alpacadecimal.RequireFromString(x).DivToScale(alpacadecimal.RequireFromString(y), exp, mode)
+			require.NoError(t, err)
+			require.Equal(t, expected, r.String(), "%s / %s", x, y)
+			require.True(t, r.IsOptimized())
+		}
+
+		check("10.00", "3.00", -2, alpacadecimal.RoundHalfAwayFromZero, "3.33")
+		check("20.00", "3.00", -2, alpacadecimal.RoundHalfAwayFromZero, "6.67")
+		check("20.00", "3.00", -2, alpacadecimal.RoundDown, "6.66")
+		check("-20.00", "3.00", -2, alpacadecimal.RoundCeil, "-6.66")
+		check("-20.00", "3.00", -2, alpacadecimal.RoundFloor, "-6.67")
+		check("0.05", "2", -2, alpacadecimal.RoundHalfEven, "0.02")
+		check("0.07", "2", -2, alpacadecimal.RoundHalfEven, "0.04")
+		check("-0.05", "2", -2, alpacadecimal.RoundHalfUp, "-0.02")
+		check("0.05", "2", -2, alpacadecimal.RoundHalfUp, "0.03")
+		check("1", "3", 0, alpacadecimal.RoundUp, "1")
+
+		// the exact quotient is rounded, unlike Div then RoundWithMode:
+		// 0.4999999999999999999 / 1 is 0.5000000000000000 with DivisionPrecision 16.
+		{
+			d := alpacadecimal.RequireFromString("0.4999999999999999999")
+			x, err := d.DivToScale(one, 0, alpacadecimal.RoundHalfAwayFromZero)
+			require.NoError(t, err)
+			require.Equal(t, "0", x.String())
+			require.Equal(t, "1", d.Div(one).Round(0).String())
+		}
+
+		_, err := one.DivToScale(alpacadecimal.Zero, -2, alpacadecimal.RoundHalfEven)
+		require.Error(t, err)
 	})
 
 	t.Run("Decimal.Equal", func(t *testing.T) {
@@ -428,6 +1960,32 @@ func TestDecimal(t *testing.T) {
 		shouldEqual(t, two, two)
 	})
 
+	t.Run("Decimal.EqualRelative", func(t *testing.T) {
+		for _, tol := range []string{"0", "0.0001", "0.5", "1", "2", "0.000000000001", "-0.1"} {
+			requireCompatible2(t, func(input1, input2 string) (bool, bool) {
+				x := alpacadecimal.RequireFromString(input1).EqualRelative(alpacadecimal.RequireFromString(input2), alpacadecimal.RequireFromString(tol))
+
+				y1 := decimal.RequireFromString(input1)
+				y2 := decimal.RequireFromString(input2)
+				y := y1.Sub(y2).Abs().LessThanOrEqual(decimal.RequireFromString(tol).Mul(decimal.Max(y1.Abs(), y2.Abs())))
+				return x, y
+			})
+		}
+
+		tol := alpacadecimal.RequireFromString("0.0001")
+		require.True(t, alpacadecimal.RequireFromString("100").EqualRelative(alpacadecimal.RequireFromString("100.01"), tol))
+		require.False(t, alpacadecimal.RequireFromString("100").EqualRelative(alpacadecimal.RequireFromString("100.02"), tol))
+		require.True(t, alpacadecimal.RequireFromString("0.0001").EqualRelative(alpacadecimal.RequireFromString("0.00010001"), tol))
+		require.False(t, alpacadecimal.RequireFromString("0.0001").EqualRelative(alpacadecimal.RequireFromString("0.00010002"), tol))
+		require.True(t, alpacadecimal.Zero.EqualRelative(alpacadecimal.Zero, alpacadecimal.Zero))
+		require.False(t, alpacadecimal.Zero.EqualRelative(alpacadecimal.RequireFromString("0.000000000001"), tol))
+
+		// difference out of int64 range
+		max := alpacadecimal.NewFromInt(9_223_371)
+		require.True(t, max.EqualRelative(max.Neg(), alpacadecimal.NewFromInt(2)))
+		require.False(t, max.EqualRelative(max.Neg(), alpacadecimal.RequireFromString("1.999999999999")))
+	})
+
 	t.Run("Decimal.Equals", func(t *testing.T) {
 		require.True(t, one.Equals(one))
 		require.False(t, one.Equals(two))
@@ -475,6 +2033,74 @@ func TestDecimal(t *testing.T) {
 		require.Equal(t, float64(1), f)
 	})
 
+	t.Run("Decimal.Float32", func(t *testing.T) {
+		requireCompatible(t, func(input string) (string, string) {
+			f, exact := alpacadecimal.RequireFromString(input).Float32()
+			g, exact2 := decimal.RequireFromString(input).Rat().Float32()
+			return fmt.Sprint(f, exact), fmt.Sprint(g, exact2)
+		})
+
+		check := func(input string, expected float32, expectedExact bool) {
+			f, exact := alpacadecimal.RequireFromString(input).Float32()
+			require.Equal(t, expected, f, input)
+			require.Equal(t, expectedExact, exact, input)
+		}
+
+		check("1", 1, true)
+		check("-16777216", -16777216, true)
+		check("16777217", 16777216, false)
+		check("0.5", 0.5, true)
+		check("0.1", 0.1, false)
+		check("123456789.25", 123456792, false)
+
+		// 2^20 + 2^-4 is a float32 tie, x is just above it so it should round up,
+		// while float32(float64) rounds x to the tie first and then to even.
+		x := alpacadecimal.RequireFromString("1048576.062500000001")
+		f, exact := x.Float32()
+		require.False(t, exact)
+		require.Equal(t, float32(1048576.125), f)
+		require.Equal(t, float32(1048576), float32(x.InexactFloat64()))
+	})
+
+	t.Run("Decimal.FMA", func(t *testing.T) {
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			x1 := alpacadecimal.RequireFromString(input1)
+			x2 := alpacadecimal.RequireFromString(input2)
+			x := x1.FMA(x2, x1)
+
+			// same value as the two-step version
+			require.True(t, x.Equal(x1.Mul(x2).Add(x1)))
+
+			y1 := decimal.RequireFromString(input1)
+			y2 := decimal.RequireFromString(input2)
+			y := y1.Mul(y2).Add(y1)
+			return x.String(), y.String()
+		})
+
+		// intermediate product out of optimized range
+		price := alpacadecimal.NewFromInt(5_000_000)
+		qty := alpacadecimal.NewFromInt(2)
+		base := alpacadecimal.NewFromInt(-9_000_000)
+		require.False(t, price.Mul(qty).IsOptimized())
+		x := price.FMA(qty, base)
+		require.True(t, x.IsOptimized())
+		require.Equal(t, "1000000", x.String())
+
+		x = price.Neg().FMA(qty, base.Neg())
+		require.True(t, x.IsOptimized())
+		require.Equal(t, "-1000000", x.String())
+
+		// final result out of optimized range
+		x = price.FMA(qty, one)
+		require.False(t, x.IsOptimized())
+		require.Equal(t, "10000001", x.String())
+
+		// product with more than 12 decimal places
+		x = alpacadecimal.RequireFromString("0.0000001").FMA(alpacadecimal.RequireFromString("0.0000001"), one)
+		require.False(t, x.IsOptimized())
+		require.Equal(t, "1.00000000000001", x.String())
+	})
+
 	t.Run("Decimal.Floor", func(t *testing.T) {
 		a1 := alpacadecimal.RequireFromString("1.234")
 		b1 := alpacadecimal.RequireFromString("1")
@@ -558,6 +2184,50 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.IsApproxInteger & RoundIfApproxInteger", func(t *testing.T) {
+		tolerance := alpacadecimal.RequireFromString("0.000000001")
+
+		for _, c := range []struct {
+			input    string
+			approx   bool
+			expected string
+		}{
+			{"3", true, "3"},
+			{"2.9999999999", true, "3"},
+			{"3.0000000001", true, "3"},
+			{"-2.9999999999", true, "-3"},
+			{"-3.0000000001", true, "-3"},
+			{"2.999999999", true, "3"},
+			{"3.000000001", true, "3"},
+			{"2.999999998", false, "2.999999998"},
+			{"3.000000002", false, "3.000000002"},
+			{"2.99", false, "2.99"},
+			{"0.5", false, "0.5"},
+			{"0.0000000000001", true, "0"},
+			{"-0.0000000000001", true, "0"},
+			{"12345678901234567890.0000000001", true, "12345678901234567890"},
+			{"12345678901234567889.9999999999", true, "12345678901234567890"},
+			{"12345678901234567890.1", false, "12345678901234567890.1"},
+		} {
+			d := alpacadecimal.RequireFromString(c.input)
+			require.Equal(t, c.approx, d.IsApproxInteger(tolerance), c.input)
+			require.Equal(t, c.expected, d.RoundIfApproxInteger(tolerance).String(), c.input)
+
+			// same for a fallback tolerance
+			require.Equal(t, c.approx, d.IsApproxInteger(alpacadecimal.NewFromDecimal(decimal.RequireFromString("0.000000001")).Add(alpacadecimal.RequireFromString("1e-20"))), c.input)
+		}
+
+		require.False(t, alpacadecimal.RequireFromString("3").IsApproxInteger(alpacadecimal.RequireFromString("-1")))
+		require.True(t, alpacadecimal.RequireFromString("3.5").IsApproxInteger(alpacadecimal.RequireFromString("0.5")))
+		require.True(t, alpacadecimal.RequireFromString("-3.5").IsApproxInteger(alpacadecimal.RequireFromString("0.5")))
+
+		requireCompatible(t, func(input string) (bool, bool) {
+			x := alpacadecimal.RequireFromString(input).IsApproxInteger(alpacadecimal.Zero)
+			y := decimal.RequireFromString(input).IsInteger()
+			return x, y
+		})
+	})
+
 	t.Run("Decimal.IsInteger", func(t *testing.T) {
 		x := alpacadecimal.RequireFromString("1.2")
 		require.False(t, x.IsInteger())
@@ -572,6 +2242,23 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.IsMultipleOf", func(t *testing.T) {
+		require.True(t, alpacadecimal.RequireFromString("1.25").IsMultipleOf(alpacadecimal.RequireFromString("0.05")))
+		require.True(t, alpacadecimal.RequireFromString("-300").IsMultipleOf(alpacadecimal.RequireFromString("100")))
+		require.False(t, alpacadecimal.RequireFromString("1.26").IsMultipleOf(alpacadecimal.RequireFromString("0.05")))
+		require.True(t, alpacadecimal.Zero.IsMultipleOf(alpacadecimal.RequireFromString("0.01")))
+		require.False(t, alpacadecimal.Zero.IsMultipleOf(alpacadecimal.Zero))
+		require.False(t, alpacadecimal.RequireFromString("1").IsMultipleOf(alpacadecimal.Zero))
+		require.True(t, alpacadecimal.RequireFromString("123456789.5").IsMultipleOf(alpacadecimal.RequireFromString("0.5")))
+		require.False(t, alpacadecimal.RequireFromString("0.0000000000001").IsMultipleOf(alpacadecimal.RequireFromString("0.000000000001")))
+
+		requireCompatible2(t, func(input1, input2 string) (bool, bool) {
+			y := decimal.RequireFromString(input2)
+			x := alpacadecimal.RequireFromString(input1).IsMultipleOf(alpacadecimal.RequireFromString(input2))
+			return x, !y.IsZero() && decimal.RequireFromString(input1).Mod(y).IsZero()
+		})
+	})
+
 	t.Run("Decimal.IsNegative", func(t *testing.T) {
 		x := alpacadecimal.RequireFromString("1.234")
 		require.False(t, x.IsNegative())
@@ -589,6 +2276,69 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.IsNegativeOne", func(t *testing.T) {
+		require.True(t, alpacadecimal.RequireFromString("-1").IsNegativeOne())
+		require.True(t, alpacadecimal.RequireFromString("-1.000").IsNegativeOne())
+		require.False(t, alpacadecimal.RequireFromString("1").IsNegativeOne())
+		require.False(t, alpacadecimal.RequireFromString("-1.000000000001").IsNegativeOne())
+
+		x := alpacadecimal.NewFromBigInt(big.NewInt(-1000), -3)
+		require.False(t, x.IsOptimized())
+		require.True(t, x.IsNegativeOne())
+
+		requireCompatible(t, func(input string) (bool, bool) {
+			x := alpacadecimal.RequireFromString(input).IsNegativeOne()
+			y := decimal.RequireFromString(input).Equal(decimal.NewFromInt(-1))
+			return x, y
+		})
+	})
+
+	t.Run("Decimal.IsOne", func(t *testing.T) {
+		require.True(t, alpacadecimal.RequireFromString("1").IsOne())
+		require.True(t, alpacadecimal.RequireFromString("1.000").IsOne())
+		require.False(t, alpacadecimal.RequireFromString("-1").IsOne())
+		require.False(t, alpacadecimal.RequireFromString("1.000000000001").IsOne())
+		require.False(t, alpacadecimal.RequireFromString("1.0000000000001").IsOne())
+
+		x := alpacadecimal.NewFromBigInt(big.NewInt(1000), -3)
+		require.False(t, x.IsOptimized())
+		require.True(t, x.IsOne())
+
+		requireCompatible(t, func(input string) (bool, bool) {
+			x := alpacadecimal.RequireFromString(input).IsOne()
+			y := decimal.RequireFromString(input).Equal(decimal.NewFromInt(1))
+			return x, y
+		})
+	})
+
+	t.Run("Decimal.IsNonNegative", func(t *testing.T) {
+		require.True(t, alpacadecimal.RequireFromString("1.234").IsNonNegative())
+		require.True(t, alpacadecimal.RequireFromString("0.0").IsNonNegative())
+		require.False(t, alpacadecimal.RequireFromString("-12").IsNonNegative())
+		require.True(t, alpacadecimal.NewFromBigInt(big.NewInt(0), -20).IsNonNegative())
+		require.False(t, alpacadecimal.NewFromBigInt(big.NewInt(-1), -20).IsNonNegative())
+
+		requireCompatible(t, func(input string) (bool, bool) {
+			x := alpacadecimal.RequireFromString(input).IsNonNegative()
+			y := decimal.RequireFromString(input).Sign() >= 0
+			return x, y
+		})
+	})
+
+	t.Run("Decimal.IsNonPositive", func(t *testing.T) {
+		require.False(t, alpacadecimal.RequireFromString("1.234").IsNonPositive())
+		require.True(t, alpacadecimal.RequireFromString("0.0").IsNonPositive())
+		require.True(t, alpacadecimal.RequireFromString("-12").IsNonPositive())
+		require.True(t, alpacadecimal.NewFromBigInt(big.NewInt(0), -20).IsNonPositive())
+		require.False(t, alpacadecimal.NewFromBigInt(big.NewInt(1), -20).IsNonPositive())
+
+		requireCompatible(t, func(input string) (bool, bool) {
+			x := alpacadecimal.RequireFromString(input).IsNonPositive()
+			y := decimal.RequireFromString(input).Sign() <= 0
+			return x, y
+		})
+	})
+
 	t.Run("Decimal.IsPositive", func(t *testing.T) {
 		x := alpacadecimal.RequireFromString("1.234")
 		require.True(t, x.IsPositive())
@@ -651,6 +2401,73 @@ func TestDecimal(t *testing.T) {
 		shouldEqual(t, x, y)
 	})
 
+	t.Run("Decimal.MarshalBinary with MarshalBinaryCompact", func(t *testing.T) {
+		defer func() { alpacadecimal.MarshalBinaryCompact = false }()
+
+		for _, input := range cases {
+			x := alpacadecimal.RequireFromString(input)
+
+			alpacadecimal.MarshalBinaryCompact = false
+			legacy, err := x.MarshalBinary()
+			require.NoError(t, err)
+			expected, err := decimal.RequireFromString(input).MarshalBinary()
+			require.NoError(t, err)
+
+			alpacadecimal.MarshalBinaryCompact = true
+			compact, err := x.MarshalBinary()
+			require.NoError(t, err)
+			if x.IsOptimized() {
+				require.Len(t, compact, 9, input)
+			} else {
+				require.Equal(t, expected, compact[1:], input)
+			}
+
+			// both are accepted regardless of the setting
+			for _, compactSetting := range []bool{false, true} {
+				alpacadecimal.MarshalBinaryCompact = compactSetting
+				for _, data := range [][]byte{legacy, expected, compact} {
+					var y alpacadecimal.Decimal
+					require.NoError(t, y.UnmarshalBinary(data), input)
+					require.True(t, x.Equal(y), input)
+
+					var z alpacadecimal.Decimal
+					require.NoError(t, z.GobDecode(data), input)
+					require.True(t, x.Equal(z), input)
+				}
+			}
+
+			// compact optimized values stay optimized
+			var y alpacadecimal.Decimal
+			require.NoError(t, y.UnmarshalBinary(compact))
+			require.Equal(t, x.IsOptimized(), y.IsOptimized(), input)
+		}
+
+		// gob streams, e.g. a shared cache written by old and new binaries
+		type entry struct {
+			Price alpacadecimal.Decimal
+			Qty   alpacadecimal.Decimal
+		}
+		in := entry{Price: alpacadecimal.RequireFromString("187.25"), Qty: alpacadecimal.RequireFromString("12345678901234567890.5")}
+		for _, compactSetting := range []bool{false, true} {
+			alpacadecimal.MarshalBinaryCompact = compactSetting
+
+			var buf bytes.Buffer
+			require.NoError(t, gob.NewEncoder(&buf).Encode(in))
+
+			alpacadecimal.MarshalBinaryCompact = !compactSetting
+			var out entry
+			require.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+			require.Equal(t, "187.25", out.Price.String())
+			require.Equal(t, "12345678901234567890.5", out.Qty.String())
+		}
+
+		// invalid compact data
+		var y alpacadecimal.Decimal
+		require.Error(t, y.UnmarshalBinary([]byte{0x81, 0, 0, 0}))
+		require.ErrorIs(t, y.UnmarshalBinary([]byte{0x81, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}), alpacadecimal.ErrOutOfRange)
+		require.Error(t, y.UnmarshalBinary([]byte{0x82}))
+	})
+
 	t.Run("Decimal.MarshalJSON", func(t *testing.T) {
 		{
 			var x alpacadecimal.Decimal
@@ -659,12 +2476,35 @@ func TestDecimal(t *testing.T) {
 			shouldEqual(t, x, alpacadecimal.New(123456, -3))
 		}
 
+		{
+			var x alpacadecimal.Decimal
+			err := x.UnmarshalJSON([]byte("\"123.456\""))
+			require.NoError(t, err)
+			require.True(t, x.IsOptimized())
+			shouldEqual(t, x, alpacadecimal.New(123456, -3))
+		}
+
 		{
 			var x alpacadecimal.Decimal
 			err := x.UnmarshalJSON([]byte("error"))
 			require.Error(t, err)
 			shouldEqual(t, alpacadecimal.Zero, x)
 		}
+
+		{
+			var v struct {
+				A alpacadecimal.Decimal     `json:"a"`
+				B alpacadecimal.NullDecimal `json:"b"`
+			}
+			v.A = alpacadecimal.NewFromInt(1)
+			v.B = alpacadecimal.NewNullDecimal(alpacadecimal.NewFromInt(1))
+
+			err := json.Unmarshal([]byte(`{"a": null, "b": null}`), &v)
+			require.NoError(t, err)
+			shouldEqual(t, alpacadecimal.Zero, v.A)
+			require.True(t, v.A.IsOptimized())
+			require.False(t, v.B.Valid)
+		}
 	})
 
 	t.Run("Decimal.MarshalText", func(t *testing.T) {
@@ -736,6 +2576,129 @@ func TestDecimal(t *testing.T) {
 
 			return r1, r2
 		})
+
+		requireCompatible2(t, func(input1, input2 string) (string, string) {
+			x := alpacadecimal.RequireFromString(input1).Mul(alpacadecimal.RequireFromString(input2)).String()
+			y := decimal.RequireFromString(input1).Mul(decimal.RequireFromString(input2)).String()
+			return x, y
+		})
+
+		// large operands
+		checkMul := func(a, b string, expected string, optimized bool) {
+			x := alpacadecimal.RequireFromString(a).Mul(alpacadecimal.RequireFromString(b))
+			require.Equal(t, expected, x.String())
+			require.Equal(t, optimized, x.IsOptimized(), "%s * %s", a, b)
+		}
+
+		checkMul("4611686", "2", "9223372", true)
+		checkMul("-9223371.999999999999", "1", "-9223371.999999999999", true)
+		checkMul("3037000.499", "3.037000499", "9223372.030926249001", false)
+		checkMul("4611686.0180184", "2", "9223372.0360368", false)
+		checkMul("4611685.9999999995", "2", "9223371.999999999", true)
+		checkMul("0.000001", "0.000001", "0.000000000001", true)
+		checkMul("0.000001", "0.0000001", "0.0000000000001", false)
+		checkMul("123456.789012", "-74.7", "-9222222.1391964", true)
+	})
+
+	t.Run("Decimal.MulRound", func(t *testing.T) {
+		for _, places := range []int32{-1, 0, 2, 6, 11, 12, 13} {
+			requireCompatible2(t, func(input1, input2 string) (string, string) {
+				x := alpacadecimal.RequireFromString(input1).MulRound(alpacadecimal.RequireFromString(input2), places).String()
+				y := decimal.RequireFromString(input1).Mul(decimal.RequireFromString(input2)).Round(places).String()
+				return x, y
+			})
+		}
+
+		check := func(a, b string, places int32, expected string) {
+			x := alpacadecimal.RequireFromString(a).MulRound(alpacadecimal.RequireFromString(b), places)
+			require.Equal(t, expected, x.String())
+			require.True(t, x.IsOptimized())
+		}
+
+		check("1.005", "1.005", 2, "1.01")   // 1.010025
+		check("-1.005", "1.005", 2, "-1.01") // half away from zero
+		check("0.15", "0.1", 2, "0.02")      // 0.015
+		check("0.14", "0.1", 2, "0.01")      // 0.014
+		check("0.000001", "0.0000015", 12, "0.000000000002")
+		check("0.000001", "0.0000014", 12, "0.000000000001")
+		check("3000000.123456", "3.000000333333", 2, "9000001.37") // product over 12 digits
+		check("9223372", "1", 0, "9223372")
+
+		require.False(t, alpacadecimal.NewFromInt(9_223_372).MulRound(alpacadecimal.RequireFromString("1.1"), 2).IsOptimized())
+	})
+
+	t.Run("Decimal.MulInt", func(t *testing.T) {
+		{
+			x := alpacadecimal.RequireFromString("1.23").MulInt(100)
+			require.Equal(t, "123", x.String())
+			require.True(t, x.IsOptimized())
+		}
+
+		{
+			x := alpacadecimal.RequireFromString("-0.000000000001").MulInt(-3)
+			require.Equal(t, "0.000000000003", x.String())
+			require.True(t, x.IsOptimized())
+		}
+
+		{
+			x := alpacadecimal.RequireFromString("9223372").MulInt(2)
+			require.Equal(t, "18446744", x.String())
+			require.False(t, x.IsOptimized())
+		}
+
+		{
+			x := alpacadecimal.RequireFromString("2").MulInt(math.MaxInt64)
+			require.Equal(t, "18446744073709551614", x.String())
+			require.False(t, x.IsOptimized())
+		}
+
+		for _, n := range []int64{0, 1, -1, 2, -7, 1000, 123456789} {
+			requireCompatible(t, func(input string) (string, string) {
+				x := alpacadecimal.RequireFromString(input).MulInt(n).String()
+				y := decimal.RequireFromString(input).Mul(decimal.NewFromInt(n)).String()
+				return x, y
+			})
+		}
+	})
+
+	t.Run("Decimal.MulPow10 & DivPow10", func(t *testing.T) {
+		{
+			x := alpacadecimal.RequireFromString("12.34").MulPow10(2)
+			require.Equal(t, "1234", x.String())
+			require.True(t, x.IsOptimized())
+
+			y := x.DivPow10(2)
+			require.Equal(t, "12.34", y.String())
+			require.True(t, y.IsOptimized())
+		}
+
+		{
+			// overflow
+			x := alpacadecimal.RequireFromString("92233.72").MulPow10(3)
+			require.Equal(t, "92233720", x.String())
+			require.False(t, x.IsOptimized())
+		}
+
+		{
+			// more than 12 decimal places, never rounded
+			x := alpacadecimal.RequireFromString("1.5").DivPow10(12)
+			require.Equal(t, "0.0000000000015", x.String())
+			require.False(t, x.IsOptimized())
+		}
+
+		for _, n := range []int32{0, 1, -1, 2, -2, 5, -5, 12, -12, 13, -13, 18, -18, 19, -19, 40, -40} {
+			requireCompatible(t, func(input string) (string, string) {
+				x := alpacadecimal.RequireFromString(input).MulPow10(n).String()
+				y := decimal.RequireFromString(input).Shift(n).String()
+				return x, y
+			})
+
+			requireCompatible(t, func(input string) (string, string) {
+				x := alpacadecimal.RequireFromString(input).DivPow10(n).String()
+				y := decimal.RequireFromString(input).Shift(-n).String()
+				return x, y
+			})
+		}
 	})
 
 	t.Run("Decimal.Neg", func(t *testing.T) {
@@ -746,6 +2709,38 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.NextUp & NextDown", func(t *testing.T) {
+		require.Equal(t, "1.000000000001", one.NextUp().String())
+		require.Equal(t, "0.999999999999", one.NextDown().String())
+		require.Equal(t, "0.000000000001", alpacadecimal.Zero.NextUp().String())
+		require.Equal(t, "-0.000000000001", alpacadecimal.Zero.NextDown().String())
+		require.Equal(t, "-1.999999999999", alpacadecimal.NewFromInt(-2).NextUp().String())
+
+		// overflow falls back
+		max := alpacadecimal.NewFromInt(9223372)
+		require.True(t, max.IsOptimized())
+		require.Equal(t, "9223372.000000000001", max.NextUp().String())
+		require.False(t, max.NextUp().IsOptimized())
+		require.Equal(t, "-9223372.000000000001", max.Neg().NextDown().String())
+		require.False(t, max.Neg().NextDown().IsOptimized())
+		require.True(t, max.NextDown().IsOptimized())
+
+		// fallback values are stepped by 10^-12 as well
+		require.Equal(t, "12345678901234567890.000000000001", alpacadecimal.RequireFromString("12345678901234567890").NextUp().String())
+		require.Equal(t, "1.000000000001000000001", alpacadecimal.RequireFromString("1.000000000000000000001").NextUp().String())
+
+		requireCompatible(t, func(input string) (string, string) {
+			d := alpacadecimal.RequireFromString(input)
+			require.True(t, d.NextUp().NextDown().Equal(d), input)
+			require.True(t, d.NextUp().GreaterThan(d), input)
+			require.True(t, d.NextDown().LessThan(d), input)
+
+			step := decimal.New(1, -12)
+			return d.NextUp().String() + d.NextDown().String(),
+				decimal.RequireFromString(input).Add(step).String() + decimal.RequireFromString(input).Sub(step).String()
+		})
+	})
+
 	t.Run("Decimal.NumDigits", func(t *testing.T) {
 		// not fully compatible
 		//
@@ -756,6 +2751,36 @@ func TestDecimal(t *testing.T) {
 		// })
 	})
 
+	t.Run("Decimal.Parse", func(t *testing.T) {
+		var d alpacadecimal.Decimal
+		for _, c := range cases {
+			require.NoError(t, d.Parse([]byte(c)))
+
+			expected := alpacadecimal.RequireFromString(c)
+			require.Equal(t, expected.String(), d.String())
+			require.Equal(t, expected.IsOptimized(), d.IsOptimized(), c)
+			require.Equal(t, expected.GetFixed(), d.GetFixed(), c)
+		}
+
+		// b is not retained
+		buf := []byte("123456789.123456789")
+		require.NoError(t, d.Parse(buf))
+		copy(buf, "999999999")
+		require.Equal(t, "123456789.123456789", d.String())
+
+		// unchanged on error
+		require.NoError(t, d.Parse([]byte("1.5")))
+		require.Error(t, d.Parse([]byte("error")))
+		require.Error(t, d.Parse(nil))
+		require.Equal(t, "1.5", d.String())
+
+		b := []byte("-1234.56")
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = d.Parse(b)
+		})
+		require.Equal(t, float64(0), allocs)
+	})
+
 	t.Run("Decimal.Pow", func(t *testing.T) {
 		for i := int64(-100); i < 100; i += 1 {
 			requireCompatible(t, func(input string) (string, string) {
@@ -771,6 +2796,93 @@ func TestDecimal(t *testing.T) {
 		}
 	})
 
+	t.Run("Decimal.PowSafe", func(t *testing.T) {
+		check := func(base, exp string, expected float64) {
+			x, err := alpacadecimal.RequireFromString(base).PowSafe(alpacadecimal.RequireFromString(exp))
+			require.NoError(t, err)
+
+			f, _ := x.Float64()
+			require.InEpsilon(t, expected, f, 1e-15, "%s^%s = %s", base, exp, x)
+			require.InEpsilon(t, math.Pow(alpacadecimal.RequireFromString(base).InexactFloat64(), alpacadecimal.RequireFromString(exp).InexactFloat64()), f, 1e-14)
+		}
+
+		check("4", "0.5", 2)
+		check("2", "0.5", math.Sqrt2)
+		check("2", "1.5", 2*math.Sqrt2)
+		check("2", "-0.5", 1/math.Sqrt2)
+		check("2", "-2.5", 1/(4*math.Sqrt2))
+		check("10", "0.25", math.Pow(10, 0.25))
+		check("1.05", "12.5", math.Pow(1.05, 12.5))
+		check("0.0001", "0.5", 0.01)
+		check("123456789.5", "0.5", math.Sqrt(123456789.5))
+
+		x, err := alpacadecimal.NewFromInt(4).PowSafe(alpacadecimal.RequireFromString("0.5"))
+		require.NoError(t, err)
+		require.Equal(t, "2", x.String())
+
+		x, err = alpacadecimal.Zero.PowSafe(alpacadecimal.RequireFromString("0.5"))
+		require.NoError(t, err)
+		require.True(t, x.IsZero())
+
+		// same as Pow for integer exponents
+		x, err = alpacadecimal.NewFromInt(-2).PowSafe(alpacadecimal.NewFromInt(3))
+		require.NoError(t, err)
+		require.Equal(t, "-8", x.String())
+
+		// no real result
+		third := alpacadecimal.NewFromInt(1).Div(alpacadecimal.NewFromInt(3))
+		_, err = alpacadecimal.NewFromInt(-8).PowSafe(third)
+		require.EqualError(t, err, "can't raise negative -8 to non-integer power 0.3333333333333333")
+		_, err = alpacadecimal.Zero.PowSafe(alpacadecimal.NewFromInt(-1))
+		require.Error(t, err)
+		_, err = alpacadecimal.Zero.PowSafe(alpacadecimal.RequireFromString("-0.5"))
+		require.Error(t, err)
+
+		// Pow keeps using the integer part of the exponent only
+		require.Equal(t, "1", alpacadecimal.NewFromInt(4).Pow(alpacadecimal.RequireFromString("0.5")).String())
+		require.NotPanics(t, func() {
+			alpacadecimal.NewFromInt(-8).Pow(third)
+		})
+	})
+
+	t.Run("Decimal.Quantize", func(t *testing.T) {
+		for _, exp := range []int32{-14, -12, -6, -2, 0} {
+			requireCompatible(t, func(input string) (string, string) {
+				x, err := alpacadecimal.RequireFromString(input).Quantize(exp, alpacadecimal.RoundHalfEven)
+				require.NoError(t, err)
+				y := decimal.RequireFromString(input).RoundBank(-exp)
+				return x.StringFixed(-exp), y.StringFixed(-exp)
+			})
+		}
+
+		check := func(input string, exp int32, mode alpacadecimal.RoundingMode, expected string, optimized bool) {
+			x, err := alpacadecimal.RequireFromString(input).Quantize(exp, mode)
+			require.NoError(t, err)
+			require.Equal(t, expected, x.String())
+			require.Equal(t, optimized, x.IsOptimized())
+			if !optimized {
+				require.Equal(t, exp, x.Exponent())
+			}
+		}
+
+		check("1.235", -2, alpacadecimal.RoundHalfEven, "1.24", true)
+		check("1.225", -2, alpacadecimal.RoundHalfEven, "1.22", true)
+		check("1.239", -2, alpacadecimal.RoundDown, "1.23", true)
+		check("-1.231", -2, alpacadecimal.RoundFloor, "-1.24", true)
+		check("1.5", -14, alpacadecimal.RoundDown, "1.5", false)
+		check("123456789.125", -2, alpacadecimal.RoundHalfEven, "123456789.12", false)
+		check("123456789.1", -2, alpacadecimal.RoundDown, "123456789.1", false)
+		check("1200", 2, alpacadecimal.RoundHalfEven, "1200", true)
+		check("123456789000", 3, alpacadecimal.RoundHalfEven, "123456789000", false)
+		check("123456789000", 3, alpacadecimal.RoundDown, "123456789000", false)
+
+		_, err := alpacadecimal.NewFromInt(1234).Quantize(2, alpacadecimal.RoundHalfEven)
+		require.Error(t, err)
+
+		_, err = alpacadecimal.RequireFromString("1200.5").Quantize(2, alpacadecimal.RoundDown)
+		require.Error(t, err)
+	})
+
 	t.Run("Decimal.QuoRem", func(t *testing.T) {
 		for i := int32(0); i < 10; i++ {
 			requireCompatible2(t, func(input1, input2 string) (string, string) {
@@ -794,8 +2906,48 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.Reciprocal & ReciprocalExact", func(t *testing.T) {
+		for _, c := range []struct {
+			input     string
+			expected  string
+			exact     bool
+			optimized bool
+		}{
+			{"1", "1", true, true},
+			{"2", "0.5", true, true},
+			{"4", "0.25", true, true},
+			{"-5", "-0.2", true, true},
+			{"8", "0.125", true, true},
+			{"0.5", "2", true, true},
+			{"0.000001", "1000000", true, true},
+			{"1024", "0.0009765625", true, true},
+			{"3", "0.3333333333333333", false, false},
+			{"-7", "-0.1428571428571429", false, false},
+			{"0.0000001", "10000000", true, false},
+			{"4096", "0.000244140625", true, true},
+			{"8192", "0.0001220703125", true, false},
+			{"12345678901234567890", "0", false, false},
+		} {
+			d := alpacadecimal.RequireFromString(c.input)
+
+			x := d.Reciprocal()
+			require.Equal(t, c.expected, x.String(), c.input)
+			require.Equal(t, decimal.NewFromInt(1).Div(decimal.RequireFromString(c.input)).String(), x.String(), c.input)
+
+			y, exact := d.ReciprocalExact()
+			require.Equal(t, c.expected, y.String(), c.input)
+			require.Equal(t, c.exact, exact, c.input)
+			require.Equal(t, c.optimized, y.IsOptimized(), c.input)
+		}
+
+		x, exact := alpacadecimal.Zero.ReciprocalExact()
+		require.False(t, exact)
+		require.True(t, x.IsZero())
+		require.Panics(t, func() { alpacadecimal.Zero.Reciprocal() })
+	})
+
 	t.Run("Decimal.Round", func(t *testing.T) {
-		for i := int32(0); i < 10; i++ {
+		for i := int32(-10); i < 10; i++ {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).Round(i).String()
 				y := decimal.RequireFromString(input).Round(i).String()
@@ -823,10 +2975,36 @@ func TestDecimal(t *testing.T) {
 
 		require.Equal(t, "-1.23456", alpacadecimal.RequireFromString("-1.23456").Round(6).String())
 		require.Equal(t, "-1.23456", decimal.RequireFromString("-1.23456").Round(6).String())
+
+		// negative places round integer digits
+		check := func(input string, places int32, expected string, optimized bool) {
+			x := alpacadecimal.RequireFromString(input).Round(places)
+			require.Equal(t, expected, x.String())
+			require.Equal(t, optimized, x.IsOptimized(), "%s.Round(%d)", input, places)
+			require.Equal(t, expected, decimal.RequireFromString(input).Round(places).String())
+		}
+
+		check("1234", -2, "1200", true)
+		check("1250", -2, "1300", true)
+		check("-1250.5", -2, "-1300", true)
+		check("49.99", -2, "0", true)
+		check("9223371.999999999999", -6, "9000000", true)
+		check("9223371.999999999999", -1, "9223370", true)
+		check("-9223366", -1, "-9223370", true)
+		check("9223368", -1, "9223370", true)
+		check("9223371", -2, "9223400", false)
+		check("8765432", -7, "10000000", false)
+
+		x := alpacadecimal.RequireFromString("1234.5678")
+		allocs := testing.AllocsPerRun(100, func() {
+			x = x.Round(-2)
+		})
+		require.Equal(t, float64(0), allocs)
+		require.Equal(t, "1200", x.String())
 	})
 
 	t.Run("Decimal.RoundBank", func(t *testing.T) {
-		for i := int32(0); i < 10; i++ {
+		for i := int32(-10); i < 10; i++ {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).RoundBank(i).String()
 				y := decimal.RequireFromString(input).RoundBank(i).String()
@@ -836,6 +3014,25 @@ func TestDecimal(t *testing.T) {
 	})
 
 	t.Run("Decimal.RoundCash", func(t *testing.T) {
+		check := func(input string, interval uint8, expected string) {
+			x := alpacadecimal.RequireFromString(input).RoundCash(interval)
+			require.Equal(t, expected, x.String())
+			require.True(t, x.IsOptimized())
+		}
+
+		check("3.43", 5, "3.45")
+		check("3.45", 10, "3.5")
+		check("3.41", 25, "3.5")
+		check("3.75", 50, "4")
+		check("3.50", 100, "4")
+		check("-3.43", 5, "-3.45")
+		check("-3.45", 10, "-3.5")
+		check("-3.49", 100, "-3")
+		check("9223371.99", 100, "9223372")
+
+		require.Panics(t, func() { alpacadecimal.NewFromInt(1).RoundCash(3) })
+		require.Panics(t, func() { decimal.NewFromInt(1).RoundCash(3) })
+
 		for _, i := range []uint8{5, 10, 25, 50, 100} {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).RoundCash(i).String()
@@ -846,7 +3043,7 @@ func TestDecimal(t *testing.T) {
 	})
 
 	t.Run("Decimal.RoundCeil", func(t *testing.T) {
-		for i := int32(0); i < 10; i++ {
+		for i := int32(-10); i < 10; i++ {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).RoundCeil(i).String()
 				y := decimal.RequireFromString(input).RoundCeil(i).String()
@@ -856,7 +3053,7 @@ func TestDecimal(t *testing.T) {
 	})
 
 	t.Run("Decimal.RoundDown", func(t *testing.T) {
-		for i := int32(0); i < 10; i++ {
+		for i := int32(-10); i < 10; i++ {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).RoundDown(i).String()
 				y := decimal.RequireFromString(input).RoundDown(i).String()
@@ -866,7 +3063,7 @@ func TestDecimal(t *testing.T) {
 	})
 
 	t.Run("Decimal.RoundFloor", func(t *testing.T) {
-		for i := int32(0); i < 10; i++ {
+		for i := int32(-10); i < 10; i++ {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).RoundFloor(i).String()
 				y := decimal.RequireFromString(input).RoundFloor(i).String()
@@ -875,14 +3072,182 @@ func TestDecimal(t *testing.T) {
 		}
 	})
 
-	t.Run("Decimal.RoundUp", func(t *testing.T) {
-		for i := int32(0); i < 10; i++ {
+	t.Run("Decimal.RoundToSignificant", func(t *testing.T) {
+		for digits := 1; digits < 20; digits++ {
+			requireCompatible(t, func(input string) (string, string) {
+				x := alpacadecimal.RequireFromString(input).RoundToSignificant(digits).String()
+
+				y := decimal.RequireFromString(input)
+				if !y.IsZero() {
+					places := int32(digits) - int32(y.NumDigits()) - y.Exponent()
+					y = y.Round(places)
+				}
+				return x, y.String()
+			})
+		}
+
+		check := func(input string, digits int, expected string) {
+			x := alpacadecimal.RequireFromString(input).RoundToSignificant(digits)
+			require.Equal(t, expected, x.String(), input)
+		}
+
+		check("1234.5", 2, "1200")
+		check("0.012345", 2, "0.012")
+		check("-0.012345", 3, "-0.0123")
+		check("0.000000000015", 1, "0.00000000002")
+		check("9.96", 2, "10")
+		check("-9.96", 2, "-10")
+		check("9223372", 1, "9000000")
+		check("123456789", 3, "123000000")
+		check("0.00000000000012345", 2, "0.00000000000012")
+		check("1.5", 12, "1.5")
+		check("0", 3, "0")
+
+		require.True(t, alpacadecimal.RequireFromString("0.012345").RoundToSignificant(2).IsOptimized())
+		require.Panics(t, func() { one.RoundToSignificant(0) })
+	})
+
+	t.Run("Decimal.RoundUp", func(t *testing.T) {
+		for i := int32(-10); i < 10; i++ {
+			requireCompatible(t, func(input string) (string, string) {
+				x := alpacadecimal.RequireFromString(input).RoundUp(i).String()
+				y := decimal.RequireFromString(input).RoundUp(i).String()
+				return x, y
+			})
+		}
+	})
+
+	t.Run("Decimal.RoundWithMode", func(t *testing.T) {
+		check := func(input string, mode alpacadecimal.RoundingMode, expected string) {
+			x := alpacadecimal.RequireFromString(input).RoundWithMode(0, mode)
+			require.Equal(t, expected, x.String())
+			require.True(t, x.IsOptimized())
+		}
+
+		inputs := []string{"2.5", "-2.5", "3.5", "-3.5", "2.4", "-2.6"}
+		expected := map[alpacadecimal.RoundingMode][]string{
+			alpacadecimal.RoundHalfAwayFromZero: {"3", "-3", "4", "-4", "2", "-3"},
+			alpacadecimal.RoundHalfEven:         {"2", "-2", "4", "-4", "2", "-3"},
+			alpacadecimal.RoundHalfUp:           {"3", "-2", "4", "-3", "2", "-3"},
+			alpacadecimal.RoundCeil:             {"3", "-2", "4", "-3", "3", "-2"},
+			alpacadecimal.RoundFloor:            {"2", "-3", "3", "-4", "2", "-3"},
+			alpacadecimal.RoundDown:             {"2", "-2", "3", "-3", "2", "-2"},
+			alpacadecimal.RoundUp:               {"3", "-3", "4", "-4", "3", "-3"},
+		}
+		for mode, outputs := range expected {
+			for i, input := range inputs {
+				check(input, mode, outputs[i])
+			}
+		}
+
+		// fallback agrees with optimized, shifting by an even offset of the same sign
+		// keeps both the tie-breaking and the rounding direction unchanged.
+		for mode, outputs := range expected {
+			for i, input := range inputs {
+				x := alpacadecimal.RequireFromString(input)
+				offset := alpacadecimal.NewFromInt(int64(100_000_000 * x.Sign()))
+				y := x.Add(offset).RoundWithMode(0, mode)
+				require.False(t, y.IsOptimized())
+				require.Equal(t, alpacadecimal.RequireFromString(outputs[i]).Add(offset).String(), y.String())
+			}
+		}
+
+		// fallback values which fit after rounding come back optimized
+		for mode := range expected {
+			x := alpacadecimal.RequireFromString("1.2345678901234567").RoundWithMode(2, mode)
+			require.True(t, x.IsOptimized())
+
+			y := alpacadecimal.RequireFromString("1234500.0000000000001").RoundWithMode(-2, mode)
+			require.True(t, y.IsOptimized())
+		}
+		{
+			price := alpacadecimal.RequireFromString("0.1234567890123")
+			qty := alpacadecimal.RequireFromString("1.5")
+			x := price.Mul(qty)
+			require.False(t, x.IsOptimized())
+
+			for _, y := range []alpacadecimal.Decimal{
+				x.RoundBank(2), x.Round(2), x.RoundCeil(2), x.RoundFloor(2), x.RoundDown(2), x.RoundUp(2),
+				x.RoundCash(5), x.Ceil(), x.Floor(), x.Truncate(2), x.RoundToSignificant(3),
+			} {
+				require.True(t, y.IsOptimized(), y.String())
+			}
+			require.Equal(t, "0.19", x.RoundBank(2).String())
+		}
+
+		require.Panics(t, func() { alpacadecimal.NewFromInt(1).RoundWithMode(0, alpacadecimal.RoundingMode(-1)) })
+		require.Panics(t, func() { alpacadecimal.NewFromInt(1).RoundWithMode(0, alpacadecimal.RoundUp+1) })
+	})
+
+	t.Run("Decimal.ScaleTo", func(t *testing.T) {
+		for _, exp := range []int32{-14, -12, -6, -2, 0, 2} {
 			requireCompatible(t, func(input string) (string, string) {
-				x := alpacadecimal.RequireFromString(input).RoundUp(i).String()
-				y := decimal.RequireFromString(input).RoundUp(i).String()
-				return x, y
+				x, err := alpacadecimal.RequireFromString(input).ScaleTo(exp)
+				if err != nil {
+					x = alpacadecimal.NewFromInt(-1)
+				}
+				y := decimal.RequireFromString(input)
+				if !y.Equal(y.Truncate(-exp)) || (exp > 0 && !y.Equal(y.Div(decimal.New(1, exp)).Truncate(0).Shift(exp))) {
+					y = decimal.NewFromInt(-1)
+				}
+				return x.String(), y.String()
 			})
 		}
+
+		check := func(input string, exp int32, optimized bool) {
+			x, err := alpacadecimal.RequireFromString(input).ScaleTo(exp)
+			require.NoError(t, err)
+			require.Equal(t, input, x.String())
+			require.Equal(t, optimized, x.IsOptimized())
+			if !optimized {
+				require.Equal(t, exp, x.Exponent())
+			}
+		}
+
+		check("1.23", -2, true)
+		check("-1.2", -2, true)
+		check("1200", 2, true)
+		check("1.5", -14, false)
+		check("123456789.12", -2, false)
+		check("123456789.1", -3, false)
+		check("123456789000", 3, false)
+
+		for _, c := range []struct {
+			input string
+			exp   int32
+		}{
+			{"1.235", -2},
+			{"-1.235", -2},
+			{"1234", 2},
+			{"123456789.125", -2},
+			{"123456789123", 3},
+			{"0.0000000000001", -12},
+		} {
+			_, err := alpacadecimal.RequireFromString(c.input).ScaleTo(c.exp)
+			require.Error(t, err, c.input)
+		}
+	})
+
+	t.Run("Decimal.Scan nil & bool", func(t *testing.T) {
+		x := alpacadecimal.NewFromInt(1)
+		err := x.Scan(nil)
+		require.EqualError(t, err, "can't scan NULL into Decimal, use NullDecimal instead")
+		shouldEqual(t, alpacadecimal.NewFromInt(1), x)
+
+		err = x.Scan(true)
+		require.EqualError(t, err, "can't scan bool true into Decimal")
+		shouldEqual(t, alpacadecimal.NewFromInt(1), x)
+
+		for _, v := range []interface{}{"", "   ", []byte{}, []byte(" \t"), sql.RawBytes(""), json.Number("")} {
+			err = x.Scan(v)
+			require.EqualError(t, err, "can't scan empty string into Decimal, use NullDecimal instead", v)
+			shouldEqual(t, alpacadecimal.NewFromInt(1), x)
+		}
+
+		var y alpacadecimal.NullDecimal
+		require.NoError(t, y.Scan(nil))
+		require.False(t, y.Valid)
+		require.Error(t, y.Scan(false))
 	})
 
 	t.Run("Decimal.Scan", func(t *testing.T) {
@@ -900,6 +3265,157 @@ func TestDecimal(t *testing.T) {
 		check("-1234")
 		check("0.123")
 		check("1.234")
+
+		{
+			var d alpacadecimal.Decimal
+			err := d.Scan([]byte("\"1.234\""))
+			require.NoError(t, err)
+			require.True(t, d.IsOptimized())
+			require.Equal(t, "1.234", d.String())
+		}
+
+		// sql.RawBytes buffer is reused by driver, the scanned value must not alias it.
+		for _, source := range []string{"1.234", "-1234.5", "123456789.123456789", "0.1234567890123456"} {
+			buf := sql.RawBytes(source)
+
+			var d alpacadecimal.Decimal
+			err := d.Scan(buf)
+			require.NoError(t, err)
+
+			for i := range buf {
+				buf[i] = '9'
+			}
+			require.Equal(t, source, d.String())
+		}
+
+		{
+			var d alpacadecimal.Decimal
+			err := d.Scan(sql.RawBytes("error"))
+			require.Error(t, err)
+		}
+
+		// surrounding whitespace, e.g. padded CHAR columns or CSV fields
+		for source, expected := range map[string]string{
+			"  1.23 ":                "1.23",
+			"\t-1234.5\n":            "-1234.5",
+			"\r\n42\r\n":             "42",
+			" \"1.5\" ":              "1.5",
+			" 123456789.123456789\t": "123456789.123456789",
+			"\t0.0000000000001 ":     "0.0000000000001",
+		} {
+			for _, value := range []interface{}{source, []byte(source)} {
+				var d alpacadecimal.Decimal
+				err := d.Scan(value)
+				require.NoError(t, err, source)
+				require.Equal(t, expected, d.String())
+				require.Equal(t, alpacadecimal.RequireFromString(expected).IsOptimized(), d.IsOptimized(), source)
+			}
+		}
+
+		for _, source := range []string{"1 .23", "1. 23", "- 1", " ", "", "1\u00a0"} {
+			for _, value := range []interface{}{source, []byte(source)} {
+				var d alpacadecimal.Decimal
+				err := d.Scan(value)
+				require.Error(t, err, source)
+			}
+		}
+
+		// scientific notation stays optimized
+		for source, expected := range map[string]string{"1.23E4": "12300", "1.23e-2": "0.0123", "-1.5E+2": "-150"} {
+			var d alpacadecimal.Decimal
+			err := d.Scan([]byte(source))
+			require.NoError(t, err)
+			require.True(t, d.IsOptimized(), source)
+			require.Equal(t, expected, d.String())
+		}
+	})
+
+	t.Run("Decimal.Scan & Value round trip", func(t *testing.T) {
+		// store then load must be idempotent, including values with more than 12 decimal places.
+		sources := append([]string{
+			"0.1234567890123456",
+			"-0.1234567890123456",
+			"0.12345678901234560000",
+			"9223372.000000000001",
+			"123456789123456789.123456789",
+			"0.0000000000001",
+			"1e-20",
+		}, cases...)
+
+		// reused across iterations, like a struct field scanned row by row.
+		var d alpacadecimal.Decimal
+		for _, source := range sources {
+			require.NoError(t, d.Scan(source))
+
+			v, err := d.Value()
+			require.NoError(t, err)
+
+			var d2 alpacadecimal.Decimal
+			require.NoError(t, d2.Scan(v))
+			require.True(t, d.Equal(d2), "%s != %s", d, d2)
+			require.Equal(t, d.IsOptimized(), d2.IsOptimized(), source)
+			require.Equal(t, d.GetFixed(), d2.GetFixed(), source)
+
+			v2, err := d2.Value()
+			require.NoError(t, err)
+			require.Equal(t, v, v2, source)
+		}
+	})
+
+	t.Run("Decimal.Scan of DECIMAL columns with scale > 12", func(t *testing.T) {
+		// e.g. mysql DECIMAL(65, 30) values have all 30 places, padded with zeros.
+		for _, c := range []struct {
+			source    string
+			expected  string
+			optimized bool
+		}{
+			{"1.230000000000000000000000000000", "1.23", true},
+			{"-1.230000000000000000000000000000", "-1.23", true},
+			{"0.000000000001000000000000000000", "0.000000000001", true},
+			{"0.000000000000000000000000000000", "0", true},
+			{"9223371.999999999999000000000000000000", "9223371.999999999999", true},
+			{"-9223372.000000000000000000000000000000", "-9223372", false},
+			{"0.000000000000100000000000000000", "0.0000000000001", false},
+			{"9223372.000000000001000000000000000000", "9223372.000000000001", false},
+			{"123456789123456789.500000000000000000000000000000", "123456789123456789.5", false},
+			{"1000000000000000000000000000000", "1000000000000000000000000000000", false},
+		} {
+			for _, source := range []interface{}{c.source, []byte(c.source)} {
+				var d alpacadecimal.Decimal
+				require.NoError(t, d.Scan(source))
+				require.Equal(t, c.expected, d.String(), c.source)
+				require.Equal(t, c.optimized, d.IsOptimized(), c.source)
+			}
+
+			d, err := alpacadecimal.NewFromString(c.source)
+			require.NoError(t, err)
+			require.Equal(t, c.optimized, d.IsOptimized(), c.source)
+		}
+	})
+
+	t.Run("Decimal.Scan with StrictFloatScan", func(t *testing.T) {
+		alpacadecimal.StrictFloatScan = true
+		defer func() { alpacadecimal.StrictFloatScan = false }()
+
+		for _, source := range []any{0.1, 1.5, -1234.25, float32(0.5), float32(-3)} {
+			var d alpacadecimal.Decimal
+			err := d.Scan(source)
+			require.NoError(t, err)
+			require.True(t, d.IsOptimized())
+			require.Equal(t, fmt.Sprint(source), d.String())
+		}
+
+		for _, source := range []any{0.1234567890123, 1e10, math.NaN(), float32(0.1)} {
+			var d alpacadecimal.Decimal
+			err := d.Scan(source)
+			require.Error(t, err, source)
+		}
+
+		// non strict mode falls back instead
+		alpacadecimal.StrictFloatScan = false
+		var d alpacadecimal.Decimal
+		require.NoError(t, d.Scan(1e10))
+		require.Equal(t, "10000000000", d.String())
 	})
 
 	t.Run("Decimal.Shift", func(t *testing.T) {
@@ -928,22 +3444,176 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.SplitN", func(t *testing.T) {
+		check := func(input string, n int, places int32, expected ...string) {
+			d := alpacadecimal.RequireFromString(input)
+			parts, err := d.SplitN(n, places)
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(parts))
+			for _, p := range parts {
+				actual = append(actual, p.String())
+			}
+			require.Equal(t, expected, actual)
+			require.True(t, alpacadecimal.Sum(parts[0], parts[1:]...).Equal(d))
+		}
+
+		check("0.1", 3, 2, "0.04", "0.03", "0.03")
+		check("-0.1", 3, 2, "-0.04", "-0.03", "-0.03")
+		check("100", 3, 2, "33.34", "33.33", "33.33")
+		check("0.02", 3, 2, "0.01", "0.01", "0")
+		check("0", 2, 2, "0", "0")
+		check("5", 1, 0, "5")
+		check("1200", 5, -2, "300", "300", "200", "200", "200")
+		check("123456789.12", 4, 2, "30864197.28", "30864197.28", "30864197.28", "30864197.28")
+		check("123456789.13", 4, 2, "30864197.29", "30864197.28", "30864197.28", "30864197.28")
+		check("0.0000000000003", 2, 13, "0.0000000000002", "0.0000000000001")
+		check("-9223371.999999999999", 2, 12, "-4611686", "-4611685.999999999999")
+
+		for _, c := range []struct {
+			input  string
+			n      int
+			places int32
+		}{
+			{"1", 0, 2},
+			{"1", -1, 2},
+			{"0.001", 3, 2},
+			{"1234", 3, -2},
+			{"123456789.123", 3, 2},
+		} {
+			_, err := alpacadecimal.RequireFromString(c.input).SplitN(c.n, c.places)
+			require.Error(t, err, c.input)
+		}
+
+		for _, n := range []int{1, 2, 3, 7} {
+			for _, places := range []int32{0, 2, 12, 14} {
+				for _, c := range cases {
+					d := alpacadecimal.RequireFromString(c).Round(places)
+					parts, err := d.SplitN(n, places)
+					require.NoError(t, err)
+					require.Len(t, parts, n)
+					require.True(t, alpacadecimal.Sum(parts[0], parts[1:]...).Equal(d), c)
+					require.True(t, parts[0].Sub(parts[n-1]).Abs().LessThanOrEqual(alpacadecimal.New(1, -places)), c)
+				}
+			}
+		}
+	})
+
 	t.Run("Decimal.String", func(t *testing.T) {
 		requireCompatible(t, func(input string) (string, string) {
 			x := alpacadecimal.RequireFromString(input).String()
 			y := decimal.RequireFromString(input).String()
 			return x, y
 		})
+
+		for _, input := range []string{"0", "1001", "-1001", "5000000", "-5000000", "9223371", "-9223371", "1000000.000000000001"} {
+			x := alpacadecimal.RequireFromString(input)
+			require.True(t, x.IsOptimized(), input)
+			require.Equal(t, input, x.String())
+		}
+	})
+
+	t.Run("Decimal.String with EnableIntCache", func(t *testing.T) {
+		alpacadecimal.EnableIntCache(100_000)
+		defer alpacadecimal.EnableIntCache(0)
+
+		requireCompatible(t, func(input string) (string, string) {
+			x := alpacadecimal.RequireFromString(input).String()
+			y := decimal.RequireFromString(input).String()
+			return x, y
+		})
+
+		for _, input := range []string{"123456", "-100000", "100000", "100001", "-100001", "99999.5"} {
+			x := alpacadecimal.RequireFromString(input)
+			require.Equal(t, input, x.String())
+
+			v, err := x.Value()
+			require.NoError(t, err)
+			require.Equal(t, input, v.(string))
+		}
+
+		// only boxing the cached string into driver.Value allocates
+		x := alpacadecimal.NewFromInt(100000)
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = x.Value()
+		})
+		require.Equal(t, float64(1), allocs)
+	})
+
+	t.Run("Decimal.StringWithMinPlaces", func(t *testing.T) {
+		for i := int32(0); i < 15; i++ {
+			requireCompatible(t, func(input string) (string, string) {
+				x := alpacadecimal.RequireFromString(input).StringWithMinPlaces(i)
+
+				// same as StringFixed with enough places to never round
+				y := decimal.RequireFromString(input)
+				places := i
+				if s := y.String(); strings.Contains(s, ".") {
+					if p := int32(len(s) - strings.Index(s, ".") - 1); p > places {
+						places = p
+					}
+				}
+				return x, y.StringFixed(places)
+			})
+		}
+
+		require.Equal(t, "1.50", alpacadecimal.RequireFromString("1.5").StringWithMinPlaces(2))
+		require.Equal(t, "1.555", alpacadecimal.RequireFromString("1.555").StringWithMinPlaces(2))
+		require.Equal(t, "-1.00", alpacadecimal.NewFromInt(-1).StringWithMinPlaces(2))
+		require.Equal(t, "0", alpacadecimal.Zero.StringWithMinPlaces(-1))
+		require.Equal(t, "0.0000000000000", alpacadecimal.Zero.StringWithMinPlaces(13))
+		require.Equal(t, "123456789.0", alpacadecimal.NewFromInt(123456789).StringWithMinPlaces(1))
+	})
+
+	t.Run("Decimal.StringExponent", func(t *testing.T) {
+		require.Equal(t, "123000000000000e-12", alpacadecimal.NewFromInt(123).StringExponent())
+		require.Equal(t, "-1e-12", alpacadecimal.RequireFromString("-0.000000000001").StringExponent())
+		require.Equal(t, "0e-12", alpacadecimal.Zero.StringExponent())
+		require.Equal(t, "15e19", alpacadecimal.RequireFromString("1.5e20").StringExponent())
+		require.Equal(t, "1e-13", alpacadecimal.RequireFromString("0.0000000000001").StringExponent())
+
+		// coefficient and exponent reconstruct the value
+		for _, c := range cases {
+			x := alpacadecimal.RequireFromString(c)
+			y := alpacadecimal.RequireFromString(x.StringExponent())
+			require.True(t, x.Equal(y), "%s != %s", x, y)
+			require.Equal(t, x.CoefficientText(10)+"e"+strconv.Itoa(int(x.Exponent())), x.StringExponent())
+		}
 	})
 
 	t.Run("Decimal.StringFixed", func(t *testing.T) {
-		for i := int32(0); i < 10; i++ {
+		for i := int32(-20); i < 20; i++ {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).StringFixed(i)
 				y := decimal.RequireFromString(input).StringFixed(i)
 				return x, y
 			})
 		}
+
+		// negative places round integer digits
+		check := func(input string, places int32, expected string) {
+			require.Equal(t, expected, alpacadecimal.RequireFromString(input).StringFixed(places))
+			require.Equal(t, expected, decimal.RequireFromString(input).StringFixed(places))
+		}
+
+		check("1234", -2, "1200")
+		check("1250", -2, "1300")
+		check("-1250", -2, "-1300")
+		check("-1234.5", -1, "-1230")
+		check("49.99", -2, "0")
+		check("-49.99", -2, "0")
+		check("50", -2, "100")
+		check("9223371.999999999999", -6, "9000000")
+		check("-9223371.999999999999", -1, "-9223370")
+		check("9223372", -5, "9200000")
+		check("9223372", -7, "10000000")
+		check("123456789.5", -3, "123457000")
+
+		x := alpacadecimal.RequireFromString("-1234.5678")
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = x.StringFixed(2)
+		})
+		require.Equal(t, float64(1), allocs)
 	})
 
 	t.Run("Decimal.StringFixedBank", func(t *testing.T) {
@@ -966,6 +3636,35 @@ func TestDecimal(t *testing.T) {
 		}
 	})
 
+	t.Run("Decimal.StringNoLeadingZero", func(t *testing.T) {
+		for input, expected := range map[string]string{
+			"0":                "0",
+			"0.5":              ".5",
+			"-0.5":             "-.5",
+			"0.05":             ".05",
+			"-0.000000000001":  "-.000000000001",
+			"0.0000000000001":  ".0000000000001",
+			"-0.0000000000001": "-.0000000000001",
+			"0.999999999999":   ".999999999999",
+			"1":                "1",
+			"-1":               "-1",
+			"1.5":              "1.5",
+			"-10.05":           "-10.05",
+			"123456789.5":      "123456789.5",
+		} {
+			require.Equal(t, expected, alpacadecimal.RequireFromString(input).StringNoLeadingZero(), input)
+		}
+
+		requireCompatible(t, func(input string) (string, string) {
+			x := alpacadecimal.RequireFromString(input).StringNoLeadingZero()
+			y := decimal.RequireFromString(input).String()
+			if strings.HasPrefix(y, "0.") || strings.HasPrefix(y, "-0.") {
+				y = strings.Replace(y, "0.", ".", 1)
+			}
+			return x, y
+		})
+	})
+
 	t.Run("Decimal.StringScaled", func(t *testing.T) {
 		for i := int32(0); i < 10; i++ {
 			requireCompatible(t, func(input string) (string, string) {
@@ -982,6 +3681,19 @@ func TestDecimal(t *testing.T) {
 			y := decimal.RequireFromString(input1).Sub(decimal.RequireFromString(input2)).String()
 			return x, y
 		})
+
+		max := alpacadecimal.NewFromInt(9_223_372)
+		require.True(t, max.Sub(one).IsOptimized())
+		require.True(t, max.Neg().Sub(one.Neg()).IsOptimized())
+		require.True(t, max.Neg().Sub(max.Neg()).IsOptimized())
+
+		x := max.Sub(one.Neg())
+		require.False(t, x.IsOptimized())
+		require.Equal(t, "9223373", x.String())
+
+		y := max.Neg().Sub(one)
+		require.False(t, y.IsOptimized())
+		require.Equal(t, "-9223373", y.String())
 	})
 
 	t.Run("Decimal.Tan", func(t *testing.T) {
@@ -992,6 +3704,45 @@ func TestDecimal(t *testing.T) {
 		})
 	})
 
+	t.Run("Decimal.Ceil & Floor & Truncate of fallback", func(t *testing.T) {
+		for _, c := range []struct {
+			input    string
+			ceil     string
+			floor    string
+			truncate string
+		}{
+			{"1.0000000000001", "2", "1", "1"},
+			{"-1.0000000000001", "-1", "-2", "-1"},
+			{"0.0000000000001", "1", "0", "0"},
+			{"-0.0000000000001", "0", "-1", "0"},
+			{"123456.00000000000000000000000000001", "123457", "123456", "123456"},
+			{"9223371.9999999999999", "9223372", "9223371", "9223371"},
+			{"-9223371.9999999999999", "-9223371", "-9223372", "-9223371"},
+		} {
+			d := alpacadecimal.RequireFromString(c.input)
+			require.False(t, d.IsOptimized(), c.input)
+
+			for _, x := range []struct {
+				result   alpacadecimal.Decimal
+				expected string
+			}{
+				{d.Ceil(), c.ceil},
+				{d.Floor(), c.floor},
+				{d.Truncate(0), c.truncate},
+			} {
+				require.Equal(t, x.expected, x.result.String(), c.input)
+				_, ok := alpacadecimal.RequireFromString(x.expected).EncodeFixed()
+				require.Equal(t, ok, x.result.IsOptimized(), c.input)
+			}
+		}
+
+		// integers out of the optimized range stay fallback
+		d := alpacadecimal.RequireFromString("12345678901234567890.5")
+		require.False(t, d.Ceil().IsOptimized())
+		require.False(t, d.Floor().IsOptimized())
+		require.False(t, d.Truncate(0).IsOptimized())
+	})
+
 	t.Run("Decimal.Truncate", func(t *testing.T) {
 		x := alpacadecimal.NewFromFloat(1.234)
 		require.Equal(t, "1", x.Truncate(0).String())
@@ -1006,8 +3757,10 @@ func TestDecimal(t *testing.T) {
 		require.Equal(t, "-1.23", y.Truncate(2).String())
 		require.Equal(t, "-1.234", y.Truncate(3).String())
 		require.Equal(t, "-1.234", y.Truncate(4).String())
+		require.Equal(t, "-1.234", y.Truncate(13).String())
+		require.Equal(t, "-1.234", y.Truncate(-1).String())
 
-		for i := int32(0); i < 10; i++ {
+		for i := int32(-2); i < 14; i++ {
 			requireCompatible(t, func(input string) (string, string) {
 				x := alpacadecimal.RequireFromString(input).Truncate(i).String()
 				y := decimal.RequireFromString(input).Truncate(i).String()
@@ -1042,6 +3795,31 @@ func TestDecimal(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, "\"123456789\"", string(json))
 		}
+
+		for _, withoutQuotes := range []bool{false, true} {
+			alpacadecimal.MarshalJSONWithoutQuotes = withoutQuotes
+			for _, c := range cases {
+				data, err := alpacadecimal.RequireFromString(c).MarshalJSON()
+				require.NoError(t, err)
+				require.True(t, json.Valid(data), "%s => %s", c, data)
+			}
+
+			// only the returned slice is allocated for optimized values
+			x := alpacadecimal.RequireFromString("-9223371.999999999999")
+			allocs := testing.AllocsPerRun(100, func() {
+				_, _ = x.MarshalJSON()
+			})
+			require.Equal(t, float64(1), allocs)
+		}
+		alpacadecimal.MarshalJSONWithoutQuotes = false
+
+		// decoding a fallback value into a reused optimized value
+		{
+			x := alpacadecimal.NewFromInt(123)
+			require.NoError(t, x.UnmarshalJSON([]byte("\"0.1234567890123456\"")))
+			require.Equal(t, alpacadecimal.RequireFromString("0.1234567890123456").GetFixed(), x.GetFixed())
+			require.Equal(t, "0.1234567890123456", x.String())
+		}
 	})
 
 	t.Run("Decimal.UnmarshalText", func(t *testing.T) {
@@ -1092,6 +3870,19 @@ func TestDecimal(t *testing.T) {
 		checkFloat(-1000.12, "-1000.12")
 		checkFloat(12345.123456789, "12345.123456789")
 		checkFloat(-12345.123456789, "-12345.123456789")
+
+		// whole numbers out of cache range
+		checkInt(5000, "5000")
+		checkInt(-100000, "-100000")
+		checkInt(9223371, "9223371")
+		checkInt(-9223371, "-9223371")
+
+		// the string and boxing it into driver.Value
+		x := alpacadecimal.NewFromInt(100000)
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = x.Value()
+		})
+		require.Equal(t, float64(2), allocs)
 	})
 
 	t.Run("Decimal.GetFixed", func(t *testing.T) {
@@ -1119,6 +3910,146 @@ func TestDecimal(t *testing.T) {
 		require.False(t, y.IsOptimized())
 	})
 
+	t.Run("Decimal.EncodeFixed & DecodeFixed", func(t *testing.T) {
+		for _, c := range cases {
+			x := alpacadecimal.RequireFromString(c)
+			v, ok := x.EncodeFixed()
+			require.Equal(t, x.IsOptimized(), ok, c)
+			if ok {
+				y := alpacadecimal.DecodeFixed(v)
+				require.True(t, y.IsOptimized())
+				shouldEqual(t, x, y)
+			}
+		}
+
+		check := func(x alpacadecimal.Decimal, expected int64, expectedOk bool) {
+			v, ok := x.EncodeFixed()
+			require.Equal(t, expectedOk, ok, x.String())
+			require.Equal(t, expected, v, x.String())
+		}
+
+		// fallback values within optimized range
+		check(alpacadecimal.NewFromBigInt(big.NewInt(123), 0), 123_000_000_000_000, true)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(-15), -1), -1_500_000_000_000, true)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(1_000), -15), 1, true)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(9), 6), 9_000_000_000_000_000_000, true)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(0), -20), 0, true)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(0), 20), 0, true)
+
+		// fallback values out of optimized range
+		check(alpacadecimal.NewFromBigInt(big.NewInt(1), -13), 0, false)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(1), 7), 0, false)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(1), 20), 0, false)
+		check(alpacadecimal.NewFromBigInt(big.NewInt(1), -20), 0, false)
+		check(alpacadecimal.NewFromInt(1234567890), 0, false)
+	})
+
+	t.Run("EncodeColumn", func(t *testing.T) {
+		ds := []alpacadecimal.Decimal{
+			alpacadecimal.RequireFromString("1.23"),
+			alpacadecimal.RequireFromString("-0.5"),
+			alpacadecimal.RequireFromString("1.234"),
+			alpacadecimal.RequireFromString("123456789.12"),
+			alpacadecimal.RequireFromString("0.00000000000001"),
+			alpacadecimal.RequireFromString("100000000000000000000"),
+			alpacadecimal.Zero,
+		}
+
+		values, valid, err := alpacadecimal.EncodeColumn(ds, 2)
+		require.NoError(t, err)
+		require.Equal(t, []int64{123, -50, 0, 12345678912, 0, 0, 0}, values)
+		require.Equal(t, []bool{true, true, false, true, false, false, true}, valid)
+
+		values, valid, err = alpacadecimal.EncodeColumn(ds[:3], 0)
+		require.NoError(t, err)
+		require.Equal(t, []int64{0, 0, 0}, values)
+		require.Equal(t, []bool{false, false, false}, valid)
+
+		values, valid, err = alpacadecimal.EncodeColumn(ds[:3], 12)
+		require.NoError(t, err)
+		require.Equal(t, []int64{1_230_000_000_000, -500_000_000_000, 1_234_000_000_000}, values)
+		require.Equal(t, []bool{true, true, true}, valid)
+
+		_, _, err = alpacadecimal.EncodeColumn(ds, 13)
+		require.Error(t, err)
+
+		_, _, err = alpacadecimal.EncodeColumn(ds, -1)
+		require.Error(t, err)
+	})
+
+	t.Run("Decimal.Validate", func(t *testing.T) {
+		require.NoError(t, one.Validate())
+		require.NoError(t, one.Validate(alpacadecimal.NonNegative(), alpacadecimal.MaxPlaces(0), alpacadecimal.InRange(one, three)))
+		require.NoError(t, alpacadecimal.Zero.Validate(alpacadecimal.NonNegative()))
+
+		err := one.Neg().Validate(alpacadecimal.NonNegative())
+		require.EqualError(t, err, "decimal -1 is negative")
+
+		err = alpacadecimal.NewFromInt(4).Validate(alpacadecimal.InRange(one, three))
+		require.EqualError(t, err, "decimal 4 is out of range [1, 3]")
+
+		// first violation is returned
+		err = alpacadecimal.NewFromInt(-4).Validate(alpacadecimal.InRange(one, three), alpacadecimal.NonNegative())
+		require.EqualError(t, err, "decimal -4 is out of range [1, 3]")
+
+		for _, input := range []string{"1.50", "-1.5", "123456789.5", "0.100000000000000"} {
+			require.NoError(t, alpacadecimal.RequireFromString(input).Validate(alpacadecimal.MaxPlaces(1)), input)
+		}
+		for _, input := range []string{"1.55", "-1.05", "123456789.55", "0.0000000000001"} {
+			err := alpacadecimal.RequireFromString(input).Validate(alpacadecimal.MaxPlaces(1))
+			require.Error(t, err, input)
+			require.Contains(t, err.Error(), "has more than 1 decimal places")
+		}
+		require.NoError(t, alpacadecimal.RequireFromString("0.000000000001").Validate(alpacadecimal.MaxPlaces(12)))
+		require.NoError(t, alpacadecimal.NewFromInt(1200).Validate(alpacadecimal.MaxPlaces(-2)))
+		require.Error(t, alpacadecimal.NewFromInt(1230).Validate(alpacadecimal.MaxPlaces(-2)))
+	})
+
+	t.Run("FixedJSON", func(t *testing.T) {
+		type order struct {
+			Price alpacadecimal.FixedJSON `json:"price"`
+		}
+
+		marshal := func(input string, places int32) string {
+			data, err := json.Marshal(order{Price: alpacadecimal.FixedJSON{Decimal: alpacadecimal.RequireFromString(input), Places: places}})
+			require.NoError(t, err)
+			return string(data)
+		}
+
+		// stable number of places regardless of the value
+		require.Equal(t, `{"price":"1.00"}`, marshal("1", 2))
+		require.Equal(t, `{"price":"1.50"}`, marshal("1.5", 2))
+		require.Equal(t, `{"price":"1.55"}`, marshal("1.549", 2))
+		require.Equal(t, `{"price":"-0.01"}`, marshal("-0.005", 2))
+		require.Equal(t, `{"price":"0.00"}`, marshal("0", 2))
+		require.Equal(t, `{"price":"12345678901234567890.00"}`, marshal("12345678901234567890", 2))
+		require.Equal(t, `{"price":"2"}`, marshal("1.5", 0))
+		require.Equal(t, `{"price":"1200"}`, marshal("1234.5", -2))
+
+		for _, c := range cases {
+			for _, places := range []int32{0, 2, 4, 12, 14} {
+				expected := decimal.RequireFromString(c).StringFixed(places)
+				require.Equal(t, `{"price":"`+expected+`"}`, marshal(c, places))
+			}
+		}
+
+		alpacadecimal.MarshalJSONWithoutQuotes = true
+		require.Equal(t, `{"price":1.50}`, marshal("1.5", 2))
+		alpacadecimal.MarshalJSONWithoutQuotes = false
+
+		// unmarshal accepts any precision and stores it
+		for _, data := range []string{`{"price":"1.23456"}`, `{"price":1.23456}`} {
+			v := order{Price: alpacadecimal.FixedJSON{Places: 2}}
+			require.NoError(t, json.Unmarshal([]byte(data), &v))
+			require.Equal(t, "1.23456", v.Price.String())
+			require.Equal(t, int32(2), v.Price.Places)
+
+			out, err := json.Marshal(v)
+			require.NoError(t, err)
+			require.Equal(t, `{"price":"1.23"}`, string(out))
+		}
+	})
+
 	t.Run("NullDecimal", func(t *testing.T) {
 		var _ alpacadecimal.NullDecimal = alpacadecimal.NullDecimal{Decimal: alpacadecimal.NewFromInt(1), Valid: true}
 		var _ alpacadecimal.NullDecimal = alpacadecimal.NullDecimal{Valid: false}
@@ -1128,6 +4059,50 @@ func TestDecimal(t *testing.T) {
 		var _ alpacadecimal.NullDecimal = alpacadecimal.NewNullDecimal(alpacadecimal.NewFromInt(123))
 	})
 
+	t.Run("NullDecimalFromPtr & NullDecimal.Ptr", func(t *testing.T) {
+		{
+			x := alpacadecimal.NullDecimalFromPtr(nil)
+			require.False(t, x.Valid)
+			require.Nil(t, x.Ptr())
+		}
+
+		{
+			d := alpacadecimal.NewFromInt(123)
+			x := alpacadecimal.NullDecimalFromPtr(&d)
+			require.True(t, x.Valid)
+			shouldEqual(t, d, x.Decimal)
+
+			p := x.Ptr()
+			require.NotNil(t, p)
+			shouldEqual(t, d, *p)
+
+			// returned pointer does not alias x
+			*p = alpacadecimal.Zero
+			shouldEqual(t, d, x.Decimal)
+		}
+	})
+
+	t.Run("NullDecimal.Add & Sub & Mul", func(t *testing.T) {
+		null := alpacadecimal.NullDecimal{}
+		x := alpacadecimal.NewNullDecimal(three)
+		y := alpacadecimal.NewNullDecimal(two)
+
+		require.Equal(t, alpacadecimal.NewNullDecimal(alpacadecimal.NewFromInt(5)), x.Add(y))
+		require.Equal(t, alpacadecimal.NewNullDecimal(one), x.Sub(y))
+		require.Equal(t, alpacadecimal.NewNullDecimal(alpacadecimal.NewFromInt(6)), x.Mul(y))
+
+		// null propagates, instead of being treated as zero
+		for _, op := range []func(a, b alpacadecimal.NullDecimal) alpacadecimal.NullDecimal{
+			alpacadecimal.NullDecimal.Add,
+			alpacadecimal.NullDecimal.Sub,
+			alpacadecimal.NullDecimal.Mul,
+		} {
+			require.False(t, op(x, null).Valid)
+			require.False(t, op(null, x).Valid)
+			require.False(t, op(null, null).Valid)
+		}
+	})
+
 	t.Run("NullDecimal.MarshalJSON", func(t *testing.T) {
 		{
 			var x alpacadecimal.NullDecimal
@@ -1180,6 +4155,33 @@ func TestDecimal(t *testing.T) {
 		}
 	})
 
+	t.Run("NullDecimal.AppendText", func(t *testing.T) {
+		requireCompatible(t, func(input string) (string, string) {
+			for _, valid := range []bool{true, false} {
+				d := alpacadecimal.NullDecimal{Decimal: alpacadecimal.RequireFromString(input), Valid: valid}
+				x, err := d.AppendText([]byte("x="))
+				require.NoError(t, err)
+				y, err := d.MarshalText()
+				require.NoError(t, err)
+				if string(x) != "x="+string(y) {
+					return string(x), "x=" + string(y)
+				}
+			}
+			return "", ""
+		})
+
+		x := alpacadecimal.NewNullDecimal(alpacadecimal.RequireFromString("-1234.5678"))
+		var invalid alpacadecimal.NullDecimal
+		buf := make([]byte, 0, 64)
+		allocs := testing.AllocsPerRun(100, func() {
+			buf, _ = x.AppendText(buf[:0])
+			buf = append(buf, ',')
+			buf, _ = invalid.AppendText(buf)
+		})
+		require.Equal(t, float64(0), allocs)
+		require.Equal(t, "-1234.5678,", string(buf))
+	})
+
 	t.Run("NullDecimal.Scan", func(t *testing.T) {
 		{
 			var x alpacadecimal.NullDecimal
@@ -1212,6 +4214,51 @@ func TestDecimal(t *testing.T) {
 			require.True(t, x.Valid) // this is to be consistent with decimal.NullDecimal
 			shouldEqual(t, alpacadecimal.Zero, x.Decimal)
 		}
+
+		{
+			var x alpacadecimal.NullDecimal
+			err := x.Scan(decimal.NewNullDecimal(decimal.RequireFromString("1.23")))
+			require.NoError(t, err)
+			require.True(t, x.Valid)
+			require.True(t, x.Decimal.IsOptimized())
+			shouldEqual(t, alpacadecimal.RequireFromString("1.23"), x.Decimal)
+		}
+
+		{
+			x := alpacadecimal.NewNullDecimal(one)
+			err := x.Scan(decimal.NullDecimal{})
+			require.NoError(t, err)
+			require.False(t, x.Valid)
+		}
+
+		// empty is NULL, same as UnmarshalText
+		for _, v := range []interface{}{"", "  ", []byte{}, []byte(" "), sql.RawBytes("")} {
+			x := alpacadecimal.NewNullDecimal(one)
+			err := x.Scan(v)
+			require.NoError(t, err, v)
+			require.False(t, x.Valid, v)
+
+			var y alpacadecimal.NullDecimal
+			require.NoError(t, y.UnmarshalText([]byte("")))
+			require.Equal(t, y.Valid, x.Valid)
+		}
+
+		{
+			var x alpacadecimal.NullDecimal
+			err := x.Scan(" 1.5 ")
+			require.NoError(t, err)
+			require.True(t, x.Valid)
+			shouldEqual(t, alpacadecimal.RequireFromString("1.5"), x.Decimal)
+		}
+	})
+
+	t.Run("NewNullDecimalFromDecimal", func(t *testing.T) {
+		x := alpacadecimal.NewNullDecimalFromDecimal(decimal.NewNullDecimal(decimal.NewFromInt(123456789)))
+		require.True(t, x.Valid)
+		require.Equal(t, "123456789", x.Decimal.String())
+
+		y := alpacadecimal.NewNullDecimalFromDecimal(decimal.NullDecimal{})
+		require.False(t, y.Valid)
 	})
 
 	t.Run("NullDecimal.UnmarshalJSON", func(t *testing.T) {
@@ -1297,3 +4344,51 @@ func TestSpecialAPIs(t *testing.T) {
 	require.Equal(t, int64(123), y.CoefficientInt64())
 	require.Equal(t, 3, y.NumDigits())
 }
+
+func FuzzParseFixed(f *testing.F) {
+	for _, c := range cases {
+		f.Add(c)
+	}
+	for _, c := range []string{"007", "+.5", "-.0", "00.50", "0.", ".", "-", "+", "1..", "\"1\"", "9223371.999999999999", "9223372", "1.23E+4", "1.23e-2", "0e-100", "1e", "-.5E-12", "1.230000000000000000000000000000", "-9223372.000000000000000000", "1.0000000000000000000000000001", "1.5e100000000000000000000", "0.000000000000000000000000", "1e-9999999", "0e-9999999", "-25e2147483000"} {
+		f.Add(c)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		x, err := alpacadecimal.NewFromString(input)
+		y, err2 := decimal.NewFromString(input)
+		require.Equal(t, err2 == nil, err == nil, fmt.Sprintf("input %q accepted by only one of the parsers", input))
+		if err != nil {
+			return
+		}
+
+		// compare canonical coefficient and exponent instead of Rat or Equal,
+		// which build 10^|exp| for inputs like "1e-9999999"
+		yCoef, yExp := canonicalFuzz(y.Coefficient(), y.Exponent())
+		xCoef, xExp := canonicalFuzz(x.Coefficient(), x.Exponent())
+		require.True(t, xCoef.Cmp(yCoef) == 0 && xExp == yExp, "input %q parsed as %s, expected %s", input, x, y)
+
+		if x.IsOptimized() {
+			// optimized value must be exactly representable with 12 precision
+			fCoef, fExp := canonicalFuzz(big.NewInt(x.GetFixed()), -12)
+			require.True(t, fCoef.Cmp(yCoef) == 0 && fExp == yExp, fmt.Sprintf("input %q is not exactly represented", input))
+		}
+	})
+}
+
+// canonicalFuzz strips trailing zeros of coef, so equal values have equal coefficient and exponent.
+func canonicalFuzz(coef *big.Int, exp int32) (*big.Int, int32) {
+	if coef.Sign() == 0 {
+		return coef, 0
+	}
+
+	ten := big.NewInt(10)
+	q, m := new(big.Int), new(big.Int)
+	for {
+		q.QuoRem(coef, ten, m)
+		if m.Sign() != 0 {
+			return coef, exp
+		}
+		coef, q = q, coef
+		exp++
+	}
+}
@@ -3,13 +3,16 @@ module github.com/alpacahq/alpacadecimal
 go 1.18
 
 require (
+	github.com/ericlagergren/decimal v0.0.0-20211103172832-aca2edc11f73
+	github.com/go-sql-driver/mysql v1.7.1
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.17
 	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.8.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/ericlagergren/decimal v0.0.0-20211103172832-aca2edc11f73 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
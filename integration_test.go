@@ -0,0 +1,126 @@
+//go:build integration
+
+package alpacadecimal_test
+
+// integration tests to round-trip Value / Scan through real sql drivers.
+//
+//	go test -tags integration -run TestIntegration .
+//
+// sqlite runs in memory and requires cgo, postgres runs only if
+// env `ALPACADECIMAL_POSTGRES_DSN` is set, e.g. "postgres://localhost/test?sslmode=disable",
+// and mysql only if env `ALPACADECIMAL_MYSQL_DSN` is set, e.g. "root@tcp(localhost:3306)/test".
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alpacahq/alpacadecimal"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+var integrationCases = []string{
+	"0",
+	"1",
+	"-1",
+	"0.01",
+	"1.23",
+	"-1234.5",
+	"999.99",
+	"1000.01",
+	"0.000000000001",
+	"-0.000000000001",
+	"9223372",
+	"-9223372",
+	"9223372.000000000001",  // fallback
+	"0.0000000000001",       // fallback
+	"123456789.123456789",   // fallback
+	"-123456789123456789.5", // fallback
+}
+
+func TestIntegrationSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	// TEXT column, sqlite NUMERIC affinity would store decimals as REAL and lose precision.
+	_, err = db.Exec(`CREATE TABLE decimals (id INTEGER PRIMARY KEY, v TEXT, nv TEXT)`)
+	require.NoError(t, err)
+
+	requireRoundTrip(t, db, "INSERT INTO decimals (id, v, nv) VALUES (?, ?, ?)", "SELECT v, nv FROM decimals WHERE id = ?")
+}
+
+func TestIntegrationPostgres(t *testing.T) {
+	dsn := os.Getenv("ALPACADECIMAL_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("ALPACADECIMAL_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TEMPORARY TABLE decimals (id INTEGER PRIMARY KEY, v NUMERIC, nv NUMERIC)`)
+	require.NoError(t, err)
+
+	requireRoundTrip(t, db, "INSERT INTO decimals (id, v, nv) VALUES ($1, $2, $3)", "SELECT v, nv FROM decimals WHERE id = $1")
+}
+
+func TestIntegrationMySQL(t *testing.T) {
+	dsn := os.Getenv("ALPACADECIMAL_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("ALPACADECIMAL_MYSQL_DSN is not set")
+	}
+
+	// server side prepared statements use the binary protocol,
+	// interpolateParams sends the query with the values inlined over the text protocol.
+	interpolated := dsn + "?interpolateParams=true"
+	if strings.Contains(dsn, "?") {
+		interpolated = dsn + "&interpolateParams=true"
+	}
+
+	for name, dsn := range map[string]string{"binary protocol": dsn, "text protocol": interpolated} {
+		dsn := dsn
+		t.Run(name, func(t *testing.T) {
+			db, err := sql.Open("mysql", dsn)
+			require.NoError(t, err)
+			defer db.Close()
+
+			// temporary tables are per connection
+			db.SetMaxOpenConns(1)
+
+			// DECIMAL(65, 30) is the max precision and scale, values are returned with all 30 places,
+			// e.g. "1.230000000000000000000000000000", which still has to scan into an optimized Decimal.
+			_, err = db.Exec(`CREATE TEMPORARY TABLE decimals (id INTEGER PRIMARY KEY, v DECIMAL(65, 30), nv DECIMAL(65, 30))`)
+			require.NoError(t, err)
+
+			requireRoundTrip(t, db, "INSERT INTO decimals (id, v, nv) VALUES (?, ?, ?)", "SELECT v, nv FROM decimals WHERE id = ?")
+		})
+	}
+}
+
+func requireRoundTrip(t *testing.T, db *sql.DB, insert, query string) {
+	for i, c := range integrationCases {
+		d := alpacadecimal.RequireFromString(c)
+
+		_, err := db.Exec(insert, i, d, alpacadecimal.NullDecimal{Decimal: d, Valid: i%2 == 0})
+		require.NoError(t, err, c)
+
+		var x alpacadecimal.Decimal
+		var y alpacadecimal.NullDecimal
+		err = db.QueryRow(query, i).Scan(&x, &y)
+		require.NoError(t, err, c)
+
+		require.True(t, d.Equal(x), "expected %s, got %s", d, x)
+		require.Equal(t, d.IsOptimized(), x.IsOptimized(), c)
+
+		require.Equal(t, i%2 == 0, y.Valid, c)
+		if y.Valid {
+			require.True(t, d.Equal(y.Decimal), "expected %s, got %s", d, y.Decimal)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build goexperiment.jsonv2
+
+package alpacadecimal
+
+// support for the json v2 interfaces, encoding/json/v2 is available
+// with `GOEXPERIMENT=jsonv2`, and by default since go 1.27.
+
+import (
+	"encoding/json/jsontext"
+)
+
+// optimized:
+// MarshalJSONTo implements the json v2 MarshalerTo interface.
+// It writes the same as MarshalJSON, i.e. a quoted string unless MarshalJSONWithoutQuotes is set,
+// straight into the encoder buffer.
+func (d Decimal) MarshalJSONTo(enc *jsontext.Encoder) error {
+	b, err := d.appendJSON(enc.AvailableBuffer())
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(b)
+}
+
+// optimized:
+// MarshalJSONTo implements the json v2 MarshalerTo interface, same as MarshalJSON.
+// It has to be implemented by FixedJSON itself, otherwise the promoted Decimal.MarshalJSONTo would be used.
+func (d FixedJSON) MarshalJSONTo(enc *jsontext.Encoder) error {
+	b, err := d.appendJSON(enc.AvailableBuffer())
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(b)
+}
+
+// optimized:
+// UnmarshalJSONFrom implements the json v2 UnmarshalerFrom interface.
+// It accepts the same as UnmarshalJSON.
+func (d *Decimal) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	v, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	return d.UnmarshalJSON(v)
+}
@@ -0,0 +1,108 @@
+//go:build goexperiment.jsonv2
+
+package alpacadecimal_test
+
+import (
+	jsonv1 "encoding/json"
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/alpacahq/alpacadecimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONv2(t *testing.T) {
+	type value struct {
+		D  alpacadecimal.Decimal     `json:"d"`
+		ND alpacadecimal.NullDecimal `json:"nd"`
+	}
+
+	t.Run("Decimal.MarshalJSONTo", func(t *testing.T) {
+		for _, withoutQuotes := range []bool{false, true} {
+			alpacadecimal.MarshalJSONWithoutQuotes = withoutQuotes
+			for _, c := range cases {
+				v := value{D: alpacadecimal.RequireFromString(c), ND: alpacadecimal.NewNullDecimal(alpacadecimal.RequireFromString(c))}
+
+				x, err := json.Marshal(v)
+				require.NoError(t, err)
+
+				y, err := jsonv1.Marshal(v)
+				require.NoError(t, err)
+
+				require.Equal(t, string(y), string(x))
+			}
+		}
+		alpacadecimal.MarshalJSONWithoutQuotes = false
+
+		data, err := json.Marshal(value{D: alpacadecimal.RequireFromString("1.5")})
+		require.NoError(t, err)
+		require.Equal(t, `{"d":"1.5","nd":null}`, string(data))
+	})
+
+	t.Run("FixedJSON.MarshalJSONTo", func(t *testing.T) {
+		type order struct {
+			Price alpacadecimal.FixedJSON `json:"price"`
+		}
+
+		for _, c := range cases {
+			v := order{Price: alpacadecimal.FixedJSON{Decimal: alpacadecimal.RequireFromString(c), Places: 2}}
+
+			x, err := json.Marshal(v)
+			require.NoError(t, err)
+
+			y, err := jsonv1.Marshal(v)
+			require.NoError(t, err)
+
+			require.Equal(t, string(y), string(x))
+		}
+
+		data, err := json.Marshal(order{Price: alpacadecimal.FixedJSON{Decimal: alpacadecimal.RequireFromString("1.5"), Places: 2}})
+		require.NoError(t, err)
+		require.Equal(t, `{"price":"1.50"}`, string(data))
+	})
+
+	t.Run("Decimal.UnmarshalJSONFrom", func(t *testing.T) {
+		for _, c := range cases {
+			for _, data := range []string{`{"d":"` + c + `","nd":"` + c + `"}`, `{"d":` + c + `,"nd":` + c + `}`} {
+				var v value
+				err := json.Unmarshal([]byte(data), &v)
+				require.NoError(t, err, data)
+
+				expected := alpacadecimal.RequireFromString(c)
+				require.True(t, expected.Equal(v.D), data)
+				require.Equal(t, expected.IsOptimized(), v.D.IsOptimized(), data)
+				require.True(t, v.ND.Valid)
+				require.True(t, expected.Equal(v.ND.Decimal), data)
+			}
+		}
+
+		v := value{D: alpacadecimal.NewFromInt(1)}
+		err := json.Unmarshal([]byte(`{"d":null,"nd":null}`), &v)
+		require.NoError(t, err)
+		require.True(t, v.D.Equal(alpacadecimal.Zero))
+		require.False(t, v.ND.Valid)
+
+		err = json.Unmarshal([]byte(`{"d":"error"}`), &v)
+		require.Error(t, err)
+	})
+}
+
+func BenchmarkJSONv2(b *testing.B) {
+	v := struct {
+		D alpacadecimal.Decimal `json:"d"`
+	}{D: alpacadecimal.RequireFromString("1234.5678")}
+
+	b.Run("encoding/json/v2", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = json.Marshal(v)
+		}
+	})
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = jsonv1.Marshal(v)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/alpacahq/alpacadecimal/mapstructurehook
+
+go 1.18
+
+require (
+	github.com/alpacahq/alpacadecimal v0.1.0
+	github.com/go-viper/mapstructure/v2 v2.5.0
+	github.com/shopspring/decimal v1.3.1
+	github.com/stretchr/testify v1.8.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,99 @@
+// Package mapstructurehook supports decoding alpacadecimal types with github.com/go-viper/mapstructure/v2,
+// e.g. to load config structs through viper.
+//
+// It is a separate module, so importing alpacadecimal doesn't pull in mapstructure.
+package mapstructurehook
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+
+	"github.com/alpacahq/alpacadecimal"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	decimalType     = reflect.TypeOf(alpacadecimal.Decimal{})
+	nullDecimalType = reflect.TypeOf(alpacadecimal.NullDecimal{})
+)
+
+// optimized:
+// DecodeHook returns a mapstructure hook to decode strings and numbers into Decimal and NullDecimal fields.
+//
+//	err := mapstructure.Decode(m, &config) // fails on decimal fields
+//
+//	dec, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{DecodeHook: mapstructurehook.DecodeHook(), Result: &config})
+//	err := dec.Decode(m)
+//
+// Strings are parsed with alpacadecimal.NewFromString, floats are converted with alpacadecimal.NewFromFloat.
+// A nil value leaves NullDecimal invalid.
+func DecodeHook() mapstructure.DecodeHookFunc {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		switch to {
+		case decimalType:
+			return decodeDecimal(data)
+		case nullDecimalType:
+			if data == nil {
+				return alpacadecimal.NullDecimal{}, nil
+			}
+			if d, ok := data.(alpacadecimal.NullDecimal); ok {
+				return d, nil
+			}
+			d, err := decodeDecimal(data)
+			if err != nil {
+				return nil, err
+			}
+			return alpacadecimal.NullDecimal{Decimal: d, Valid: true}, nil
+		default:
+			return data, nil
+		}
+	}
+}
+
+func decodeDecimal(data interface{}) (alpacadecimal.Decimal, error) {
+	switch v := data.(type) {
+	case alpacadecimal.Decimal:
+		return v, nil
+	case string:
+		return alpacadecimal.NewFromString(v)
+	case json.Number:
+		return alpacadecimal.NewFromJSONNumber(v)
+	case int:
+		return alpacadecimal.NewFromInt(int64(v)), nil
+	case int8:
+		return alpacadecimal.NewFromInt(int64(v)), nil
+	case int16:
+		return alpacadecimal.NewFromInt(int64(v)), nil
+	case int32:
+		return alpacadecimal.NewFromInt(int64(v)), nil
+	case int64:
+		return alpacadecimal.NewFromInt(v), nil
+	case uint:
+		return decodeUint64(uint64(v)), nil
+	case uint8:
+		return alpacadecimal.NewFromInt(int64(v)), nil
+	case uint16:
+		return alpacadecimal.NewFromInt(int64(v)), nil
+	case uint32:
+		return alpacadecimal.NewFromInt(int64(v)), nil
+	case uint64:
+		return decodeUint64(v), nil
+	case float32:
+		return alpacadecimal.NewFromDecimal(decimal.NewFromFloat32(v)), nil
+	case float64:
+		return alpacadecimal.NewFromFloat(v), nil
+	default:
+		return alpacadecimal.Zero, fmt.Errorf("can't decode %T into Decimal", data)
+	}
+}
+
+func decodeUint64(v uint64) alpacadecimal.Decimal {
+	if v <= math.MaxInt64 {
+		return alpacadecimal.NewFromInt(int64(v))
+	}
+	return alpacadecimal.NewFromBigInt(new(big.Int).SetUint64(v), 0)
+}
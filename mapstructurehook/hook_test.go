@@ -0,0 +1,88 @@
+package mapstructurehook_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/alpacahq/alpacadecimal"
+	"github.com/alpacahq/alpacadecimal/mapstructurehook"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var cases = []string{
+	"0", "1", "-1", "0.1", "-0.000000000001", "1.23", "123456.789", "9223372", "-9223372",
+	"9223372.000000000001", "12345678901234567890", "0.0000000000001", "1e20",
+}
+
+func TestDecodeHook(t *testing.T) {
+	type config struct {
+		D  alpacadecimal.Decimal             `mapstructure:"d"`
+		ND alpacadecimal.NullDecimal         `mapstructure:"nd"`
+		P  *alpacadecimal.Decimal            `mapstructure:"p"`
+		S  []alpacadecimal.Decimal           `mapstructure:"s"`
+		N  struct{ D alpacadecimal.Decimal } `mapstructure:"n"`
+	}
+
+	decode := func(m map[string]interface{}) (config, error) {
+		var c config
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{DecodeHook: mapstructurehook.DecodeHook(), Result: &c})
+		require.NoError(t, err)
+		return c, dec.Decode(m)
+	}
+
+	t.Run("strings", func(t *testing.T) {
+		for _, input := range cases {
+			c, err := decode(map[string]interface{}{"d": input, "nd": input, "p": input, "s": []string{input}, "n": map[string]interface{}{"D": input}})
+			require.NoError(t, err)
+
+			expected := alpacadecimal.RequireFromString(input)
+			require.True(t, expected.Equal(c.D), input)
+			require.Equal(t, expected.IsOptimized(), c.D.IsOptimized(), input)
+			require.True(t, c.ND.Valid)
+			require.True(t, expected.Equal(c.ND.Decimal), input)
+			require.True(t, expected.Equal(*c.P), input)
+			require.True(t, expected.Equal(c.S[0]), input)
+			require.True(t, expected.Equal(c.N.D), input)
+		}
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		for _, x := range []interface{}{int(-12), int8(-12), int16(-12), int32(-12), int64(-12), float32(-12), float64(-12), json.Number("-12")} {
+			c, err := decode(map[string]interface{}{"d": x, "nd": x})
+			require.NoError(t, err)
+			require.Equal(t, "-12", c.D.String())
+			require.True(t, c.D.IsOptimized())
+			require.Equal(t, "-12", c.ND.Decimal.String())
+		}
+
+		for _, x := range []interface{}{uint(12), uint8(12), uint16(12), uint32(12), uint64(12)} {
+			c, err := decode(map[string]interface{}{"d": x})
+			require.NoError(t, err)
+			require.Equal(t, "12", c.D.String())
+		}
+
+		c, err := decode(map[string]interface{}{"d": uint64(math.MaxUint64), "nd": 1.25})
+		require.NoError(t, err)
+		require.Equal(t, "18446744073709551615", c.D.String())
+		require.Equal(t, "1.25", c.ND.Decimal.String())
+	})
+
+	t.Run("null", func(t *testing.T) {
+		c, err := decode(map[string]interface{}{"d": "1", "nd": nil, "p": nil})
+		require.NoError(t, err)
+		require.False(t, c.ND.Valid)
+		require.Nil(t, c.P)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, x := range []interface{}{"abc", "", true, []string{"1"}, map[string]interface{}{}} {
+			_, err := decode(map[string]interface{}{"d": x})
+			require.Error(t, err, x)
+
+			_, err = decode(map[string]interface{}{"nd": x})
+			require.Error(t, err, x)
+		}
+	})
+}
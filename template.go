@@ -0,0 +1,68 @@
+package alpacadecimal
+
+// support for text/template and html/template, e.g. for reports and emails.
+
+import (
+	"text/template"
+)
+
+// optimized:
+// TemplateFuncMap returns template functions to format Decimal values.
+//
+//	round    func(places int32, d Decimal) Decimal // d.Round(places)
+//	fixed    func(places int32, d Decimal) string  // d.StringFixed(places)
+//	humanize func(d Decimal) string                // d.String() with thousands separators, e.g. "-1,234,567.891"
+//	currency func(d Decimal) string                // d.StringFixed(2) with thousands separators and a dollar sign, e.g. "-$1,234.50"
+//
+// The value comes last so it can be piped, e.g.
+//
+//	tmpl := template.Must(template.New("").Funcs(alpacadecimal.TemplateFuncMap()).Parse(`{{ .Price | round 2 }} {{ .Total | currency }}`))
+//
+// For html/template, convert it with html/template.FuncMap(alpacadecimal.TemplateFuncMap()).
+func TemplateFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"round": func(places int32, d Decimal) Decimal {
+			return d.Round(places)
+		},
+		"fixed": func(places int32, d Decimal) string {
+			return d.StringFixed(places)
+		},
+		"humanize": func(d Decimal) string {
+			return groupThousands(d.String(), "")
+		},
+		"currency": func(d Decimal) string {
+			return groupThousands(d.StringFixed(2), "$")
+		},
+	}
+}
+
+// groupThousands inserts commas into the integer part of a formatted decimal string,
+// and the prefix between the sign and the digits.
+func groupThousands(s string, prefix string) string {
+	neg := len(s) > 0 && s[0] == '-'
+	if neg {
+		s = s[1:]
+	}
+
+	n := len(s)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '.' {
+			n = i
+			break
+		}
+	}
+
+	b := make([]byte, 0, len(s)+len(prefix)+n/3+1)
+	if neg {
+		b = append(b, '-')
+	}
+	b = append(b, prefix...)
+	for i := 0; i < n; i++ {
+		if i > 0 && (n-i)%3 == 0 {
+			b = append(b, ',')
+		}
+		b = append(b, s[i])
+	}
+	b = append(b, s[n:]...)
+	return string(b)
+}
@@ -0,0 +1,49 @@
+package alpacadecimal_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/alpacahq/alpacadecimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFuncMap(t *testing.T) {
+	execute := func(text string, d alpacadecimal.Decimal) string {
+		tmpl := template.Must(template.New("").Funcs(alpacadecimal.TemplateFuncMap()).Parse(text))
+		var sb strings.Builder
+		require.NoError(t, tmpl.Execute(&sb, d))
+		return sb.String()
+	}
+
+	for _, c := range []struct {
+		input    string
+		round    string
+		fixed    string
+		humanize string
+		currency string
+	}{
+		{"0", "0", "0.000", "0", "$0.00"},
+		{"1.005", "1.01", "1.005", "1.005", "$1.01"},
+		{"-12.5", "-12.5", "-12.500", "-12.5", "-$12.50"},
+		{"999.999", "1000", "999.999", "999.999", "$1,000.00"},
+		{"1234567.891", "1234567.89", "1234567.891", "1,234,567.891", "$1,234,567.89"},
+		{"-123456.7", "-123456.7", "-123456.700", "-123,456.7", "-$123,456.70"},
+		{"12345678901234567890.5", "12345678901234567890.5", "12345678901234567890.500", "12,345,678,901,234,567,890.5", "$12,345,678,901,234,567,890.50"},
+	} {
+		d := alpacadecimal.RequireFromString(c.input)
+		require.Equal(t, c.round, execute(`{{ . | round 2 }}`, d), c.input)
+		require.Equal(t, c.fixed, execute(`{{ fixed 3 . }}`, d), c.input)
+		require.Equal(t, c.humanize, execute(`{{ humanize . }}`, d), c.input)
+		require.Equal(t, c.currency, execute(`{{ . | currency }}`, d), c.input)
+	}
+
+	t.Run("html/template", func(t *testing.T) {
+		tmpl := htmltemplate.Must(htmltemplate.New("").Funcs(htmltemplate.FuncMap(alpacadecimal.TemplateFuncMap())).Parse(`<b>{{ . | currency }}</b>`))
+		var sb strings.Builder
+		require.NoError(t, tmpl.Execute(&sb, alpacadecimal.RequireFromString("-1234.5")))
+		require.Equal(t, "<b>-$1,234.50</b>", sb.String())
+	})
+}